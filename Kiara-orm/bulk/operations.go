@@ -2,18 +2,30 @@ package bulk
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"reflect"
 	"strings"
+	"time"
 	
 	"github.com/Flavio-coutinho/Kiara-orm/dialect"
 	"github.com/Flavio-coutinho/Kiara-orm/schema"
+	"github.com/Flavio-coutinho/Kiara-orm/timestamp"
+	"github.com/Flavio-coutinho/Kiara-orm/types"
+	"github.com/Flavio-coutinho/Kiara-orm/versioning"
 )
 
+// execer abstrai *sql.DB e *sql.Tx para execução de comandos
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
 // BulkOperation gerencia operações em lote
 type BulkOperation struct {
 	dialect dialect.Dialect
 	mapping *schema.TableMapping
 	batch   int // Tamanho do lote
+	clock   timestamp.Clock
 }
 
 // NewBulkOperation cria uma nova instância de BulkOperation
@@ -22,9 +34,16 @@ func NewBulkOperation(dialect dialect.Dialect, mapping *schema.TableMapping, bat
 		dialect: dialect,
 		mapping: mapping,
 		batch:   batchSize,
+		clock:   time.Now,
 	}
 }
 
+// WithClock define o relógio usado nos timestamps automáticos
+func (b *BulkOperation) WithClock(clock timestamp.Clock) *BulkOperation {
+	b.clock = clock
+	return b
+}
+
 // BulkInsert insere múltiplos registros
 func (b *BulkOperation) BulkInsert(ctx context.Context, db interface{}, records []interface{}) error {
 	if len(records) == 0 {
@@ -110,8 +129,13 @@ func (b *BulkOperation) insertBatch(ctx context.Context, db interface{}, batch [
 	// Valores
 	values := make([]interface{}, 0)
 	placeholders := make([]string, len(batch))
+	now := b.clock()
 	
 	for i, record := range batch {
+		// Todos os registros do lote recebem o mesmo created_at/updated_at
+		timestamp.SetCreateTime(b.mapping, reflect.ValueOf(record), now)
+		versioning.Init(b.mapping, reflect.ValueOf(record))
+		
 		placeholders[i] = b.buildValuePlaceholders(len(columns))
 		values = append(values, b.extractValues(record)...)
 	}
@@ -120,16 +144,89 @@ func (b *BulkOperation) insertBatch(ctx context.Context, db interface{}, batch [
 	
 	// Executa a query
 	query := builder.String()
-	_, err := db.(interface{ ExecContext(context.Context, string, ...interface{}) error }).
-		ExecContext(ctx, query, values...)
+	_, err := db.(execer).ExecContext(ctx, query, values...)
 	
 	return err
 }
 
-// updateBatch atualiza um lote de registros
+// updateBatch atualiza um lote de registros, um UPDATE por registro identificado
+// pela chave primária. Com coluna de versão, cada linha segue o locking otimista
+// e um conflito interrompe o lote com *versioning.StaleObjectError.
 func (b *BulkOperation) updateBatch(ctx context.Context, db interface{}, batch []interface{}, conditions map[string]interface{}) error {
-	// Implementação similar ao insertBatch, mas para UPDATE
-	// ...
+	var pk types.FieldMapping
+	for _, field := range b.mapping.Fields {
+		if field.IsPrimaryKey {
+			pk = field
+		}
+	}
+	if pk.Name == "" {
+		return fmt.Errorf("tabela %s não possui chave primária", b.mapping.TableName)
+	}
+	
+	versionField, hasVersion := versioning.Field(b.mapping)
+	now := b.clock()
+	
+	for _, record := range batch {
+		v := reflect.Indirect(reflect.ValueOf(record))
+		timestamp.SetUpdateTime(b.mapping, v, now)
+		
+		var version int64
+		if hasVersion {
+			version = versioning.Get(v, versionField)
+		}
+		
+		sets := make([]string, 0)
+		values := make([]interface{}, 0)
+		for _, field := range b.mapping.Fields {
+			if field.IsPrimaryKey || field.IsAutoInc || field.AutoCreateTime != types.AutoTimeNone {
+				continue
+			}
+			
+			sets = append(sets, fmt.Sprintf("%s = ?", b.dialect.Quote(field.Name)))
+			if field.IsVersion {
+				values = append(values, version+1)
+			} else {
+				values = append(values, v.FieldByName(field.FieldName).Interface())
+			}
+		}
+		
+		where := []string{fmt.Sprintf("%s = ?", b.dialect.Quote(pk.Name))}
+		values = append(values, v.FieldByName(pk.FieldName).Interface())
+		
+		for column, value := range conditions {
+			where = append(where, fmt.Sprintf("%s = ?", b.dialect.Quote(column)))
+			values = append(values, value)
+		}
+		
+		if hasVersion {
+			where = append(where, fmt.Sprintf("%s = ?", b.dialect.Quote(versionField.Name)))
+			values = append(values, version)
+		}
+		
+		query := fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+			b.dialect.Quote(b.mapping.TableName),
+			strings.Join(sets, ", "),
+			strings.Join(where, " AND "))
+		
+		result, err := db.(execer).ExecContext(ctx, query, values...)
+		if err != nil {
+			return err
+		}
+		
+		if !hasVersion {
+			continue
+		}
+		
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return &versioning.StaleObjectError{Table: b.mapping.TableName, Version: version}
+		}
+		versioning.Set(v, versionField, version+1)
+	}
+	
 	return nil
 }
 
@@ -151,6 +248,14 @@ func (b *BulkOperation) buildValuePlaceholders(count int) string {
 
 func (b *BulkOperation) extractValues(record interface{}) []interface{} {
 	// Extrai valores do registro usando reflection
-	// ...
-	return nil
+	v := reflect.Indirect(reflect.ValueOf(record))
+	
+	values := make([]interface{}, 0, len(b.mapping.Fields))
+	for _, field := range b.mapping.Fields {
+		if field.IsAutoInc {
+			continue
+		}
+		values = append(values, v.FieldByName(field.FieldName).Interface())
+	}
+	return values
 } 
\ No newline at end of file
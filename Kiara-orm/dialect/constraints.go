@@ -0,0 +1,53 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+	
+	"github.com/Flavio-coutinho/Kiara-orm/types"
+)
+
+// foreignKeySQL gera a cláusula CONSTRAINT ... FOREIGN KEY, comum aos dialetos
+func foreignKeySQL(quote func(string) string, fk types.ForeignKeyMapping) string {
+	clause := fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+		quote(fk.Name), quote(fk.Column), quote(fk.ReferenceTable), quote(fk.ReferenceColumn))
+	
+	if fk.OnDelete != "" {
+		clause += " ON DELETE " + fk.OnDelete
+	}
+	if fk.OnUpdate != "" {
+		clause += " ON UPDATE " + fk.OnUpdate
+	}
+	return clause
+}
+
+// CheckConstraintName retorna o nome da constraint criada pela opção check de uma coluna
+func CheckConstraintName(table, column string) string {
+	return fmt.Sprintf("chk_%s_%s", table, column)
+}
+
+// defaultSQL gera a cláusula DEFAULT da coluna
+func defaultSQL(field types.FieldMapping) string {
+	if field.Default == "" {
+		return ""
+	}
+	return " DEFAULT " + field.Default
+}
+
+// checkSQL gera a constraint CHECK nomeada da coluna
+func checkSQL(quote func(string) string, table string, field types.FieldMapping) string {
+	if field.Check == "" {
+		return ""
+	}
+	return fmt.Sprintf(" CONSTRAINT %s CHECK (%s)", quote(CheckConstraintName(table, field.Name)), field.Check)
+}
+
+// addCheckSQL gera o ALTER TABLE que adiciona a constraint CHECK da coluna
+func addCheckSQL(quote func(string) string, table string, field types.FieldMapping) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD%s", quote(table), checkSQL(quote, table, field))
+}
+
+// quoteString coloca um texto entre aspas simples, duplicando as internas
+func quoteString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
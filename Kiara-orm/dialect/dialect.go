@@ -6,25 +6,76 @@ import "github.com/Flavio-coutinho/Kiara-orm/types"
 type Dialect interface {
     // GetDataTypeSQL converte um tipo do ORM para o tipo SQL correspondente
     GetDataTypeSQL(field types.FieldMapping) string
-    
+
     // Quote coloca identificadores entre aspas de acordo com o dialeto
     Quote(identifier string) string
-    
+
     // Placeholder retorna o placeholder para parâmetros preparados (?, $1, etc)
     Placeholder(index int) string
-    
+
     // AutoIncrementSQL retorna a sintaxe para auto incremento
     AutoIncrementSQL() string
-    
+
     // CreateTableSQL gera o SQL para criar uma tabela
     CreateTableSQL(table types.TableMapping) string
-    
+
     // AddColumnSQL gera o SQL para adicionar uma coluna
     AddColumnSQL(table string, field types.FieldMapping) string
-    
+
     // DropColumnSQL gera o SQL para remover uma coluna
     DropColumnSQL(table, column string) string
-    
+
+    // AlterColumnSQL gera os comandos que alteram tipo e nulidade de uma coluna.
+    // Retorna nil quando o dialeto não suporta a alteração direta.
+    AlterColumnSQL(table string, field types.FieldMapping) []string
+
     // CreateIndexSQL gera o SQL para criar um índice
     CreateIndexSQL(table, indexName string, columns []string, unique bool) string
+
+    // IndexSQL gera o SQL para criar um índice declarado no mapeamento, com método
+    // e condição parcial quando o dialeto os suporta
+    IndexSQL(table string, index types.IndexMapping) string
+
+    // DropIndexSQL gera o SQL para remover um índice
+    DropIndexSQL(table, indexName string) string
+
+    // AddCheckSQL gera o SQL que adiciona a constraint CHECK de uma coluna existente.
+    // Retorna "" quando o dialeto não suporta a alteração direta.
+    AddCheckSQL(table string, field types.FieldMapping) string
+
+    // DropCheckSQL gera o SQL que remove uma constraint CHECK. Retorna "" quando
+    // o dialeto não suporta a alteração direta.
+    DropCheckSQL(table, name string) string
+
+    // CommentSQL gera o comando que define o comentário de uma coluna. Retorna ""
+    // quando o comentário faz parte da definição da coluna ou não é suportado.
+    CommentSQL(table string, field types.FieldMapping) string
+
+    // AddForeignKeySQL gera o SQL para adicionar uma chave estrangeira a uma tabela
+    // existente. Retorna "" quando o dialeto não suporta a alteração direta.
+    AddForeignKeySQL(table string, fk types.ForeignKeyMapping) string
+
+    // DropForeignKeySQL gera o SQL para remover uma chave estrangeira.
+    // Retorna "" quando o dialeto não suporta a alteração direta.
+    DropForeignKeySQL(table, name string) string
+
+    // SavepointSQL gera o SQL para criar um savepoint
+    SavepointSQL(name string) string
+
+    // RollbackToSavepointSQL gera o SQL para desfazer as alterações até o savepoint
+    RollbackToSavepointSQL(name string) string
+
+    // ReleaseSavepointSQL gera o SQL para liberar um savepoint
+    ReleaseSavepointSQL(name string) string
+
+    // LockSQL retorna a cláusula de bloqueio de linhas do SELECT (FOR UPDATE),
+    // pulando as linhas já bloqueadas quando skipLocked é verdadeiro
+    LockSQL(skipLocked bool) string
+
+    // SupportsTransactionalDDL indica se comandos DDL podem ser desfeitos em transação
+    SupportsTransactionalDDL() bool
+
+    // IsRetryableError indica se o erro é uma falha transitória (serialização,
+    // deadlock ou banco ocupado) em que a transação pode ser repetida
+    IsRetryableError(err error) bool
 } 
\ No newline at end of file
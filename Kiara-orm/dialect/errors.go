@@ -0,0 +1,50 @@
+package dialect
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// Os drivers não são importados aqui: os erros são inspecionados pelos
+// métodos e campos que expõem (SQLState, Number, Code) ou pela mensagem.
+
+// sqlState retorna o SQLSTATE de um erro que implementa SQLState() (pgx, lib/pq)
+func sqlState(err error) (string, bool) {
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		return stateErr.SQLState(), true
+	}
+	return "", false
+}
+
+// errorCode procura na cadeia de erros um campo numérico com o nome informado,
+// como Number em *mysql.MySQLError ou Code em sqlite3.Error
+func errorCode(err error, field string) (int64, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		v := reflect.Indirect(reflect.ValueOf(err))
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		
+		f := v.FieldByName(field)
+		switch f.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return f.Int(), true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return int64(f.Uint()), true
+		}
+	}
+	return 0, false
+}
+
+// messageContains verifica se a mensagem do erro contém algum dos trechos
+func messageContains(err error, fragments ...string) bool {
+	msg := err.Error()
+	for _, fragment := range fragments {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
@@ -77,6 +77,8 @@ func (m *MySQL) CreateTableSQL(table types.TableMapping) string {
 			column += " UNIQUE"
 		}
 		
+		column += defaultSQL(field) + commentSQL(field) + checkSQL(m.Quote, table.TableName, field)
+		
 		if field.IsPrimaryKey {
 			primaryKeys = append(primaryKeys, m.Quote(field.Name))
 		}
@@ -88,6 +90,10 @@ func (m *MySQL) CreateTableSQL(table types.TableMapping) string {
 		columns = append(columns, fmt.Sprintf("  PRIMARY KEY (%s)", strings.Join(primaryKeys, ", ")))
 	}
 	
+	for _, fk := range table.ForeignKeys {
+		columns = append(columns, "  "+foreignKeySQL(m.Quote, fk))
+	}
+	
 	builder.WriteString(strings.Join(columns, ",\n"))
 	builder.WriteString("\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;")
 	
@@ -112,6 +118,10 @@ func (m *MySQL) AddColumnSQL(table string, field types.FieldMapping) string {
 		builder.WriteString(" UNIQUE")
 	}
 	
+	builder.WriteString(defaultSQL(field))
+	builder.WriteString(commentSQL(field))
+	builder.WriteString(checkSQL(m.Quote, table, field))
+	
 	return builder.String()
 }
 
@@ -121,26 +131,131 @@ func (m *MySQL) DropColumnSQL(table, column string) string {
 		m.Quote(column))
 }
 
+func (m *MySQL) AlterColumnSQL(table string, field types.FieldMapping) []string {
+	var builder strings.Builder
+	
+	builder.WriteString("ALTER TABLE ")
+	builder.WriteString(m.Quote(table))
+	builder.WriteString(" MODIFY COLUMN ")
+	builder.WriteString(m.Quote(field.Name))
+	builder.WriteString(" ")
+	builder.WriteString(m.GetDataTypeSQL(field))
+	
+	if !field.IsNullable {
+		builder.WriteString(" NOT NULL")
+	}
+	
+	if field.IsAutoInc {
+		builder.WriteString(" " + m.AutoIncrementSQL())
+	}
+	
+	// UNIQUE e CHECK não são repetidos: MODIFY criaria um segundo índice ou constraint
+	builder.WriteString(defaultSQL(field))
+	builder.WriteString(commentSQL(field))
+	return []string{builder.String()}
+}
+
+func (m *MySQL) AddCheckSQL(table string, field types.FieldMapping) string {
+	return addCheckSQL(m.Quote, table, field)
+}
+
+func (m *MySQL) DropCheckSQL(table, name string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP CHECK %s", m.Quote(table), m.Quote(name))
+}
+
+// CommentSQL retorna "": no MySQL o comentário faz parte da definição da coluna
+func (m *MySQL) CommentSQL(table string, field types.FieldMapping) string {
+	return ""
+}
+
+// commentSQL gera a cláusula COMMENT da definição de coluna
+func commentSQL(field types.FieldMapping) string {
+	if field.Comment == "" {
+		return ""
+	}
+	return " COMMENT " + quoteString(field.Comment)
+}
+
 func (m *MySQL) CreateIndexSQL(table, indexName string, columns []string, unique bool) string {
+	return m.IndexSQL(table, types.IndexMapping{Name: indexName, Columns: columns, Unique: unique})
+}
+
+func (m *MySQL) IndexSQL(table string, index types.IndexMapping) string {
 	var builder strings.Builder
 	
 	builder.WriteString("CREATE ")
-	if unique {
+	if index.Unique {
 		builder.WriteString("UNIQUE ")
 	}
 	builder.WriteString("INDEX ")
-	builder.WriteString(m.Quote(indexName))
+	builder.WriteString(m.Quote(index.Name))
 	builder.WriteString(" ON ")
 	builder.WriteString(m.Quote(table))
 	builder.WriteString(" (")
 	
-	quotedColumns := make([]string, len(columns))
-	for i, col := range columns {
+	quotedColumns := make([]string, len(index.Columns))
+	for i, col := range index.Columns {
 		quotedColumns[i] = m.Quote(col)
 	}
 	
 	builder.WriteString(strings.Join(quotedColumns, ", "))
 	builder.WriteString(")")
 	
+	// O MySQL só aceita BTREE e HASH e não tem índices parciais
+	if method := strings.ToUpper(index.Method); method == "BTREE" || method == "HASH" {
+		builder.WriteString(" USING ")
+		builder.WriteString(method)
+	}
+	
 	return builder.String()
 }
+
+func (m *MySQL) DropIndexSQL(table, indexName string) string {
+	return fmt.Sprintf("DROP INDEX %s ON %s", m.Quote(indexName), m.Quote(table))
+}
+
+func (m *MySQL) AddForeignKeySQL(table string, fk types.ForeignKeyMapping) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD %s", m.Quote(table), foreignKeySQL(m.Quote, fk))
+}
+
+func (m *MySQL) DropForeignKeySQL(table, name string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s", m.Quote(table), m.Quote(name))
+}
+
+func (m *MySQL) SavepointSQL(name string) string {
+	return "SAVEPOINT " + m.Quote(name)
+}
+
+func (m *MySQL) RollbackToSavepointSQL(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + m.Quote(name)
+}
+
+func (m *MySQL) ReleaseSavepointSQL(name string) string {
+	return "RELEASE SAVEPOINT " + m.Quote(name)
+}
+
+// LockSQL usa FOR UPDATE (SKIP LOCKED requer MySQL 8.0+)
+func (m *MySQL) LockSQL(skipLocked bool) string {
+	if skipLocked {
+		return "FOR UPDATE SKIP LOCKED"
+	}
+	return "FOR UPDATE"
+}
+
+// SupportsTransactionalDDL retorna false: o MySQL faz commit implícito a cada comando DDL
+func (m *MySQL) SupportsTransactionalDDL() bool {
+	return false
+}
+
+// IsRetryableError reconhece os erros 1213 (deadlock) e 1205 (lock wait timeout)
+func (m *MySQL) IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	
+	if number, ok := errorCode(err, "Number"); ok {
+		return number == 1213 || number == 1205
+	}
+	
+	return messageContains(err, "Error 1213", "Error 1205")
+}
@@ -73,6 +73,8 @@ func (p *PostgreSQL) CreateTableSQL(table types.TableMapping) string {
 			column += " UNIQUE"
 		}
 		
+		column += defaultSQL(field) + checkSQL(p.Quote, table.TableName, field)
+		
 		if field.IsPrimaryKey {
 			primaryKeys = append(primaryKeys, p.Quote(field.Name))
 		}
@@ -84,6 +86,10 @@ func (p *PostgreSQL) CreateTableSQL(table types.TableMapping) string {
 		columns = append(columns, fmt.Sprintf("  PRIMARY KEY (%s)", strings.Join(primaryKeys, ", ")))
 	}
 	
+	for _, fk := range table.ForeignKeys {
+		columns = append(columns, "  "+foreignKeySQL(p.Quote, fk))
+	}
+	
 	builder.WriteString(strings.Join(columns, ",\n"))
 	builder.WriteString("\n);")
 	
@@ -108,6 +114,9 @@ func (p *PostgreSQL) AddColumnSQL(table string, field types.FieldMapping) string
 		builder.WriteString(" UNIQUE")
 	}
 	
+	builder.WriteString(defaultSQL(field))
+	builder.WriteString(checkSQL(p.Quote, table, field))
+	
 	return builder.String()
 }
 
@@ -117,26 +126,130 @@ func (p *PostgreSQL) DropColumnSQL(table, column string) string {
 		p.Quote(column))
 }
 
+func (p *PostgreSQL) AlterColumnSQL(table string, field types.FieldMapping) []string {
+	// SERIAL só existe na criação; a coluna é um INTEGER com sequência
+	dataType := p.GetDataTypeSQL(field)
+	if field.IsAutoInc {
+		dataType = "INTEGER"
+	}
+	
+	nullability := "SET NOT NULL"
+	if field.IsNullable {
+		nullability = "DROP NOT NULL"
+	}
+	
+	statements := []string{
+		fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s", p.Quote(table), p.Quote(field.Name), dataType),
+		fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s", p.Quote(table), p.Quote(field.Name), nullability),
+	}
+	
+	if field.Default != "" {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s", p.Quote(table), p.Quote(field.Name), field.Default))
+	}
+	return statements
+}
+
+func (p *PostgreSQL) AddCheckSQL(table string, field types.FieldMapping) string {
+	return addCheckSQL(p.Quote, table, field)
+}
+
+func (p *PostgreSQL) DropCheckSQL(table, name string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", p.Quote(table), p.Quote(name))
+}
+
+// CommentSQL usa COMMENT ON COLUMN, pois o PostgreSQL não aceita o comentário na definição
+func (p *PostgreSQL) CommentSQL(table string, field types.FieldMapping) string {
+	if field.Comment == "" {
+		return ""
+	}
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", p.Quote(table), p.Quote(field.Name), quoteString(field.Comment))
+}
+
 func (p *PostgreSQL) CreateIndexSQL(table, indexName string, columns []string, unique bool) string {
+	return p.IndexSQL(table, types.IndexMapping{Name: indexName, Columns: columns, Unique: unique})
+}
+
+func (p *PostgreSQL) IndexSQL(table string, index types.IndexMapping) string {
 	var builder strings.Builder
 	
 	builder.WriteString("CREATE ")
-	if unique {
+	if index.Unique {
 		builder.WriteString("UNIQUE ")
 	}
 	builder.WriteString("INDEX ")
-	builder.WriteString(p.Quote(indexName))
+	builder.WriteString(p.Quote(index.Name))
 	builder.WriteString(" ON ")
 	builder.WriteString(p.Quote(table))
+	
+	if index.Method != "" {
+		builder.WriteString(" USING ")
+		builder.WriteString(strings.ToUpper(index.Method))
+	}
+	
 	builder.WriteString(" (")
 	
-	quotedColumns := make([]string, len(columns))
-	for i, col := range columns {
+	quotedColumns := make([]string, len(index.Columns))
+	for i, col := range index.Columns {
 		quotedColumns[i] = p.Quote(col)
 	}
 	
 	builder.WriteString(strings.Join(quotedColumns, ", "))
 	builder.WriteString(")")
 	
+	if index.Where != "" {
+		builder.WriteString(" WHERE ")
+		builder.WriteString(index.Where)
+	}
+	
 	return builder.String()
 }
+
+func (p *PostgreSQL) DropIndexSQL(table, indexName string) string {
+	return "DROP INDEX " + p.Quote(indexName)
+}
+
+func (p *PostgreSQL) AddForeignKeySQL(table string, fk types.ForeignKeyMapping) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD %s", p.Quote(table), foreignKeySQL(p.Quote, fk))
+}
+
+func (p *PostgreSQL) DropForeignKeySQL(table, name string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", p.Quote(table), p.Quote(name))
+}
+
+func (p *PostgreSQL) SavepointSQL(name string) string {
+	return "SAVEPOINT " + p.Quote(name)
+}
+
+func (p *PostgreSQL) RollbackToSavepointSQL(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + p.Quote(name)
+}
+
+func (p *PostgreSQL) ReleaseSavepointSQL(name string) string {
+	return "RELEASE SAVEPOINT " + p.Quote(name)
+}
+
+// LockSQL usa FOR UPDATE
+func (p *PostgreSQL) LockSQL(skipLocked bool) string {
+	if skipLocked {
+		return "FOR UPDATE SKIP LOCKED"
+	}
+	return "FOR UPDATE"
+}
+
+// SupportsTransactionalDDL retorna true: o PostgreSQL executa DDL dentro de transações
+func (p *PostgreSQL) SupportsTransactionalDDL() bool {
+	return true
+}
+
+// IsRetryableError reconhece os SQLSTATE 40001 (serialization_failure) e 40P01 (deadlock_detected)
+func (p *PostgreSQL) IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	
+	if state, ok := sqlState(err); ok {
+		return state == "40001" || state == "40P01"
+	}
+	
+	return messageContains(err, "could not serialize access", "deadlock detected")
+}
@@ -77,6 +77,8 @@ func (s *SQLite) CreateTableSQL(table types.TableMapping) string {
 			column += " UNIQUE"
 		}
 		
+		column += defaultSQL(field) + checkSQL(s.Quote, table.TableName, field)
+		
 		if field.IsPrimaryKey && !field.IsAutoInc {
 			primaryKeys = append(primaryKeys, s.Quote(field.Name))
 		}
@@ -88,6 +90,10 @@ func (s *SQLite) CreateTableSQL(table types.TableMapping) string {
 		columns = append(columns, fmt.Sprintf("  PRIMARY KEY (%s)", strings.Join(primaryKeys, ", ")))
 	}
 	
+	for _, fk := range table.ForeignKeys {
+		columns = append(columns, "  "+foreignKeySQL(s.Quote, fk))
+	}
+	
 	builder.WriteString(strings.Join(columns, ",\n"))
 	builder.WriteString("\n);")
 	
@@ -112,36 +118,120 @@ func (s *SQLite) AddColumnSQL(table string, field types.FieldMapping) string {
 		builder.WriteString(" UNIQUE")
 	}
 	
+	builder.WriteString(defaultSQL(field))
+	builder.WriteString(checkSQL(s.Quote, table, field))
+	
 	return builder.String()
 }
 
+// DropColumnSQL usa o DROP COLUMN nativo, disponível a partir do SQLite 3.35.
+// Em versões anteriores, ou para colunas indexadas, o Migrator recria a tabela.
 func (s *SQLite) DropColumnSQL(table, column string) string {
-	// SQLite não suporta DROP COLUMN diretamente
-	// É necessário recriar a tabela sem a coluna
-	return fmt.Sprintf("-- SQLite não suporta DROP COLUMN diretamente.\n"+
-		"-- É necessário recriar a tabela sem a coluna %s", s.Quote(column))
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s",
+		s.Quote(table),
+		s.Quote(column))
+}
+
+func (s *SQLite) AlterColumnSQL(table string, field types.FieldMapping) []string {
+	// SQLite não suporta ALTER COLUMN: é necessário recriar a tabela
+	return nil
 }
 
 func (s *SQLite) CreateIndexSQL(table, indexName string, columns []string, unique bool) string {
+	return s.IndexSQL(table, types.IndexMapping{Name: indexName, Columns: columns, Unique: unique})
+}
+
+func (s *SQLite) IndexSQL(table string, index types.IndexMapping) string {
 	var builder strings.Builder
 	
 	builder.WriteString("CREATE ")
-	if unique {
+	if index.Unique {
 		builder.WriteString("UNIQUE ")
 	}
 	builder.WriteString("INDEX ")
-	builder.WriteString(s.Quote(indexName))
+	builder.WriteString(s.Quote(index.Name))
 	builder.WriteString(" ON ")
 	builder.WriteString(s.Quote(table))
 	builder.WriteString(" (")
 	
-	quotedColumns := make([]string, len(columns))
-	for i, col := range columns {
+	quotedColumns := make([]string, len(index.Columns))
+	for i, col := range index.Columns {
 		quotedColumns[i] = s.Quote(col)
 	}
 	
 	builder.WriteString(strings.Join(quotedColumns, ", "))
 	builder.WriteString(")")
 	
+	// O SQLite aceita índices parciais, mas tem um único método de acesso
+	if index.Where != "" {
+		builder.WriteString(" WHERE ")
+		builder.WriteString(index.Where)
+	}
+	
 	return builder.String()
 }
+
+func (s *SQLite) DropIndexSQL(table, indexName string) string {
+	return "DROP INDEX " + s.Quote(indexName)
+}
+
+// AddCheckSQL retorna "": o SQLite só aceita CHECK na criação da tabela ou da coluna
+func (s *SQLite) AddCheckSQL(table string, field types.FieldMapping) string {
+	return ""
+}
+
+// DropCheckSQL retorna "": o SQLite só remove CHECK recriando a tabela
+func (s *SQLite) DropCheckSQL(table, name string) string {
+	return ""
+}
+
+// CommentSQL retorna "": o SQLite não guarda comentários de colunas
+func (s *SQLite) CommentSQL(table string, field types.FieldMapping) string {
+	return ""
+}
+
+// AddForeignKeySQL retorna "": o SQLite só aceita chaves estrangeiras na criação da tabela
+func (s *SQLite) AddForeignKeySQL(table string, fk types.ForeignKeyMapping) string {
+	return ""
+}
+
+// DropForeignKeySQL retorna "": a chave estrangeira só sai recriando a tabela
+func (s *SQLite) DropForeignKeySQL(table, name string) string {
+	return ""
+}
+
+func (s *SQLite) SavepointSQL(name string) string {
+	return "SAVEPOINT " + s.Quote(name)
+}
+
+func (s *SQLite) RollbackToSavepointSQL(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + s.Quote(name)
+}
+
+func (s *SQLite) ReleaseSavepointSQL(name string) string {
+	return "RELEASE SAVEPOINT " + s.Quote(name)
+}
+
+// LockSQL retorna vazio: o SQLite bloqueia o banco inteiro na escrita e não
+// suporta bloqueio de linhas
+func (s *SQLite) LockSQL(skipLocked bool) string {
+	return ""
+}
+
+// SupportsTransactionalDDL retorna true: o SQLite executa DDL dentro de transações
+func (s *SQLite) SupportsTransactionalDDL() bool {
+	return true
+}
+
+// IsRetryableError reconhece SQLITE_BUSY (5) e SQLITE_LOCKED (6)
+func (s *SQLite) IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	
+	if code, ok := errorCode(err, "Code"); ok {
+		return code == 5 || code == 6
+	}
+	
+	return messageContains(err, "database is locked", "database table is locked", "SQLITE_BUSY")
+}
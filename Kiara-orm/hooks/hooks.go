@@ -17,8 +17,25 @@ const (
 	AfterDelete
 	BeforeQuery
 	AfterQuery
+	BeforeAssociate
+	AfterAssociate
+	BeforeDissociate
+	AfterDissociate
+	
+	// Executados apenas após o commit da transação (ou logo após a operação, fora
+	// de transação). Erros são registrados no log e não afetam a operação.
+	AfterCreateCommit
+	AfterUpdateCommit
+	AfterDeleteCommit
 )
 
+// AssociationChange é o valor recebido pelos hooks de associação
+type AssociationChange struct {
+	Owner   interface{}   // Registro dono do relacionamento
+	Field   string        // Campo do relacionamento (ex.: "Tags")
+	Related []interface{} // Registros associados ou desassociados
+}
+
 // Hook representa uma função de hook
 type Hook func(ctx context.Context, value interface{}) error
 
@@ -9,11 +9,12 @@ import (
 type MetricType string
 
 const (
-	QueryExecution  MetricType = "query_execution"
-	CacheHit        MetricType = "cache_hit"
-	CacheMiss       MetricType = "cache_miss"
-	ConnectionUsage MetricType = "connection_usage"
-	ErrorCount      MetricType = "error_count"
+	QueryExecution   MetricType = "query_execution"
+	CacheHit         MetricType = "cache_hit"
+	CacheMiss        MetricType = "cache_miss"
+	ConnectionUsage  MetricType = "connection_usage"
+	ErrorCount       MetricType = "error_count"
+	TransactionRetry MetricType = "transaction_retry"
 )
 
 // Metric representa uma métrica coletada
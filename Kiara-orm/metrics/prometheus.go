@@ -59,6 +59,12 @@ func (p *PrometheusExporter) Export(metric Metric) error {
 		if ok {
 			counter.With(metric.Labels).Add(metric.Value)
 		}
+		
+	case TransactionRetry:
+		counter, ok := p.counters["transaction_retries"]
+		if ok {
+			counter.With(metric.Labels).Add(metric.Value)
+		}
 	}
 	
 	return nil
@@ -109,6 +115,17 @@ func (p *PrometheusExporter) registerDefaultMetrics() {
 	)
 	p.registry.MustRegister(errorCounter)
 	p.counters["errors_total"] = errorCounter
+	
+	// Contador de novas tentativas de transação
+	retryCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "orm_transaction_retries_total",
+			Help: "Total number of transaction retries",
+		},
+		[]string{"attempt"},
+	)
+	p.registry.MustRegister(retryCounter)
+	p.counters["transaction_retries"] = retryCounter
 }
 
 // GetRegistry retorna o registro Prometheus
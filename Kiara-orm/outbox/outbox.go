@@ -0,0 +1,54 @@
+package outbox
+
+import (
+	"context"
+	"time"
+	
+	"github.com/Flavio-coutinho/Kiara-orm/session"
+)
+
+// Status dos eventos na tabela de outbox
+const (
+	StatusPending = "pending" // Aguardando publicação
+	StatusSent    = "sent"    // Publicado
+	StatusFailed  = "failed"  // Tentativas esgotadas
+)
+
+// Event é um evento de domínio gravado na tabela de outbox
+type Event struct {
+	TableName     struct{}   `db:"outbox_events"`
+	ID            int64      `db:"id,primarykey,autoincrement"`
+	Topic         string     `db:"topic,size:255"`
+	Key           string     `db:"event_key,size:255"`
+	Payload       string     `db:"payload"`
+	Status        string     `db:"status,size:20"`
+	Attempts      int        `db:"attempts"`
+	LastError     string     `db:"last_error"`
+	NextAttemptAt time.Time  `db:"next_attempt_at"`
+	CreatedAt     time.Time  `db:"created_at"`
+	SentAt        *time.Time `db:"sent_at"`
+}
+
+// Migrate cria ou atualiza a tabela de outbox pelo schema.Migrator da sessão
+func Migrate(ctx context.Context, sess *session.Session) error {
+	return sess.AutoMigrate(ctx, &Event{})
+}
+
+// Enqueue grava os eventos na tabela de outbox. Chamado com a sessão de uma
+// transação (ou um contexto que a carrega), os eventos são gravados atomicamente
+// com as demais alterações e só ficam visíveis ao Relay após o commit.
+func Enqueue(ctx context.Context, sess *session.Session, events ...*Event) error {
+	now := sess.Clock()().UTC()
+	for _, event := range events {
+		event.Status = StatusPending
+		event.Attempts = 0
+		if event.NextAttemptAt.IsZero() {
+			event.NextAttemptAt = now
+		}
+		
+		if err := sess.Model(&Event{}).Create(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
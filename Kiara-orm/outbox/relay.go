@@ -0,0 +1,168 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+	
+	"github.com/Flavio-coutinho/Kiara-orm/query"
+	"github.com/Flavio-coutinho/Kiara-orm/schema"
+	"github.com/Flavio-coutinho/Kiara-orm/session"
+	"github.com/Flavio-coutinho/Kiara-orm/transaction"
+)
+
+// Publisher entrega os eventos ao destino (fila, broker, webhook)
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// PublisherFunc adapta uma função à interface Publisher
+type PublisherFunc func(ctx context.Context, event Event) error
+
+// Publish chama a função
+func (f PublisherFunc) Publish(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
+// RelayOptions configura o Relay
+type RelayOptions struct {
+	BatchSize int                     // Eventos processados por transação
+	Interval  time.Duration           // Espera entre consultas quando não há eventos
+	Retry     transaction.RetryPolicy // Espera entre tentativas; MaxRetries esgotado marca o evento como failed
+}
+
+// DefaultRelayOptions retorna as opções padrão do Relay
+func DefaultRelayOptions() RelayOptions {
+	return RelayOptions{
+		BatchSize: 100,
+		Interval:  time.Second,
+		Retry: transaction.RetryPolicy{
+			MaxRetries: 10,
+			BaseDelay:  time.Second,
+			MaxDelay:   5 * time.Minute,
+		},
+	}
+}
+
+// Relay lê os eventos pendentes da outbox e os entrega ao Publisher
+type Relay struct {
+	session   *session.Session
+	publisher Publisher
+	options   RelayOptions
+	table     string
+}
+
+// NewRelay cria um novo Relay. Campos zerados das opções recebem os valores de
+// DefaultRelayOptions; uma Retry zerada usa a política padrão inteira.
+func NewRelay(sess *session.Session, publisher Publisher, options RelayOptions) *Relay {
+	mapping, _ := schema.NewParser().Parse(&Event{})
+	
+	return &Relay{
+		session:   sess,
+		publisher: publisher,
+		options:   options.withDefaults(),
+		table:     mapping.TableName,
+	}
+}
+
+// withDefaults preenche os campos zerados: BatchSize 0 geraria LIMIT 0 e faria
+// o Run consultar o banco sem pausa
+func (o RelayOptions) withDefaults() RelayOptions {
+	defaults := DefaultRelayOptions()
+	
+	if o.BatchSize <= 0 {
+		o.BatchSize = defaults.BatchSize
+	}
+	if o.Interval <= 0 {
+		o.Interval = defaults.Interval
+	}
+	
+	if o.Retry == (transaction.RetryPolicy{}) {
+		o.Retry = defaults.Retry
+	}
+	if o.Retry.BaseDelay <= 0 {
+		o.Retry.BaseDelay = defaults.Retry.BaseDelay
+	}
+	if o.Retry.MaxDelay <= 0 {
+		o.Retry.MaxDelay = defaults.Retry.MaxDelay
+	}
+	return o
+}
+
+// Run processa a outbox até o contexto ser cancelado. Erros são registrados no log.
+func (r *Relay) Run(ctx context.Context) error {
+	for {
+		processed, err := r.ProcessBatch(ctx)
+		if err != nil {
+			r.session.Logger().Error(ctx, "Erro ao processar outbox: %v", err)
+		}
+		
+		// Lote cheio: provavelmente há mais eventos pendentes
+		if err == nil && processed == r.options.BatchSize {
+			continue
+		}
+		
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.options.Interval):
+		}
+	}
+}
+
+// ProcessBatch publica um lote de eventos pendentes e retorna quantos foram processados.
+// As linhas são bloqueadas com FOR UPDATE SKIP LOCKED, o que permite vários relays em paralelo.
+func (r *Relay) ProcessBatch(ctx context.Context) (int, error) {
+	processed := 0
+	
+	err := r.session.Transaction(session.WithoutTx(ctx), func(tx *session.Session) error {
+		now := r.session.Clock()().UTC()
+		
+		builder := tx.Query().
+			Table(r.table).
+			Where("status", query.OpEq, StatusPending).
+			Where("next_attempt_at", query.OpLe, now).
+			OrderBy("id", false).
+			Limit(r.options.BatchSize).
+			ForUpdate(true)
+		
+		var events []Event
+		if err := tx.Exec(builder).Query(ctx, &events); err != nil {
+			return fmt.Errorf("erro ao buscar eventos pendentes: %w", err)
+		}
+		
+		for i := range events {
+			if err := r.deliver(ctx, tx, &events[i], now); err != nil {
+				return err
+			}
+			processed++
+		}
+		return nil
+	})
+	
+	return processed, err
+}
+
+// deliver publica o evento e grava o resultado: enviado, ou a próxima tentativa com backoff
+func (r *Relay) deliver(ctx context.Context, tx *session.Session, event *Event, now time.Time) error {
+	if err := r.publisher.Publish(ctx, *event); err != nil {
+		event.Attempts++
+		event.LastError = err.Error()
+		
+		if event.Attempts > r.options.Retry.MaxRetries {
+			event.Status = StatusFailed
+			r.session.Logger().Error(ctx, "Evento %d da outbox falhou após %d tentativas: %v", event.ID, event.Attempts, err)
+		} else {
+			event.NextAttemptAt = now.Add(r.options.Retry.Backoff(event.Attempts - 1))
+		}
+	} else {
+		event.Status = StatusSent
+		event.SentAt = &now
+	}
+	
+	return tx.Model(&Event{}).Update(ctx, event, query.Condition{
+		Column:    "id",
+		Operation: query.OpEq,
+		Value:     event.ID,
+	})
+}
@@ -2,6 +2,7 @@ package query
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	
 	"github.com/Flavio-coutinho/Kiara-orm/dialect"
@@ -20,8 +21,20 @@ const (
 	OpLike  Operation = "LIKE"
 	OpILike Operation = "ILIKE"
 	OpIn    Operation = "IN"
+	
+	// Operações sem valor
+	OpIsNull    Operation = "IS NULL"
+	OpIsNotNull Operation = "IS NOT NULL"
+	
+	// Subqueries: o valor da condição é um *Builder
+	OpExists    Operation = "EXISTS"
+	OpNotExists Operation = "NOT EXISTS"
 )
 
+// ColumnRef referencia uma coluna como valor de uma condição,
+// por exemplo em subqueries correlacionadas ("users.id")
+type ColumnRef string
+
 // Condition representa uma condição WHERE
 type Condition struct {
 	Column    string
@@ -34,6 +47,7 @@ type Builder struct {
 	dialect    dialect.Dialect
 	table      string
 	columns    []string
+	rawColumns []string
 	conditions []Condition
 	orderBy    []string
 	limit      *int
@@ -42,6 +56,7 @@ type Builder struct {
 	joins      []string
 	groupBy    []string
 	having     []Condition
+	lock       string
 }
 
 // NewBuilder cria uma nova instância do Builder
@@ -66,6 +81,18 @@ func (b *Builder) Select(columns ...string) *Builder {
 	return b
 }
 
+// SelectRaw adiciona expressões sem aspas à seleção, como COUNT(*)
+func (b *Builder) SelectRaw(expressions ...string) *Builder {
+	b.rawColumns = append(b.rawColumns, expressions...)
+	return b
+}
+
+// SelectAs seleciona uma coluna com um apelido, por exemplo "users.name" AS "User__name"
+func (b *Builder) SelectAs(column, alias string) *Builder {
+	b.rawColumns = append(b.rawColumns, fmt.Sprintf("%s AS %s", b.quoteColumn(column), b.dialect.Quote(alias)))
+	return b
+}
+
 // Where adiciona uma condição WHERE
 func (b *Builder) Where(column string, op Operation, value interface{}) *Builder {
 	b.conditions = append(b.conditions, Condition{
@@ -77,6 +104,21 @@ func (b *Builder) Where(column string, op Operation, value interface{}) *Builder
 	return b
 }
 
+// WhereColumn compara duas colunas, por exemplo "posts.user_id" = "users.id"
+func (b *Builder) WhereColumn(column string, op Operation, other string) *Builder {
+	return b.Where(column, op, ColumnRef(other))
+}
+
+// WhereExists adiciona uma condição EXISTS com a subquery informada
+func (b *Builder) WhereExists(sub *Builder) *Builder {
+	return b.Where("", OpExists, sub)
+}
+
+// WhereNotExists adiciona uma condição NOT EXISTS com a subquery informada
+func (b *Builder) WhereNotExists(sub *Builder) *Builder {
+	return b.Where("", OpNotExists, sub)
+}
+
 // OrderBy adiciona ordenação
 func (b *Builder) OrderBy(column string, desc bool) *Builder {
 	order := b.dialect.Quote(column)
@@ -99,6 +141,13 @@ func (b *Builder) Offset(offset int) *Builder {
 	return b
 }
 
+// ForUpdate bloqueia as linhas selecionadas até o fim da transação.
+// Com skipLocked, linhas já bloqueadas por outra transação são ignoradas.
+func (b *Builder) ForUpdate(skipLocked bool) *Builder {
+	b.lock = b.dialect.LockSQL(skipLocked)
+	return b
+}
+
 // Join adiciona uma cláusula JOIN
 func (b *Builder) Join(joinType, table, condition string) *Builder {
 	join := fmt.Sprintf("%s JOIN %s ON %s", 
@@ -109,6 +158,17 @@ func (b *Builder) Join(joinType, table, condition string) *Builder {
 	return b
 }
 
+// JoinAs adiciona uma cláusula JOIN com apelido para a tabela
+func (b *Builder) JoinAs(joinType, table, alias, condition string) *Builder {
+	join := fmt.Sprintf("%s JOIN %s %s ON %s",
+		joinType,
+		b.dialect.Quote(table),
+		b.dialect.Quote(alias),
+		condition)
+	b.joins = append(b.joins, join)
+	return b
+}
+
 // GroupBy adiciona agrupamento
 func (b *Builder) GroupBy(columns ...string) *Builder {
 	for _, col := range columns {
@@ -130,18 +190,27 @@ func (b *Builder) Having(column string, op Operation, value interface{}) *Builde
 
 // BuildSelect constrói uma query SELECT
 func (b *Builder) BuildSelect() (string, []interface{}) {
+	params := make([]interface{}, 0, len(b.params))
+	query := b.renderSelect(&params)
+	return query, params
+}
+
+// renderSelect renderiza o SELECT acumulando os parâmetros em params,
+// o que mantém a numeração dos placeholders em subqueries
+func (b *Builder) renderSelect(params *[]interface{}) string {
 	var builder strings.Builder
 	
 	builder.WriteString("SELECT ")
 	
 	// Colunas
-	if len(b.columns) == 0 {
+	if len(b.columns) == 0 && len(b.rawColumns) == 0 {
 		builder.WriteString("*")
 	} else {
-		quotedColumns := make([]string, len(b.columns))
-		for i, col := range b.columns {
-			quotedColumns[i] = b.dialect.Quote(col)
+		quotedColumns := make([]string, 0, len(b.columns)+len(b.rawColumns))
+		for _, col := range b.columns {
+			quotedColumns = append(quotedColumns, b.quoteColumn(col))
 		}
+		quotedColumns = append(quotedColumns, b.rawColumns...)
 		builder.WriteString(strings.Join(quotedColumns, ", "))
 	}
 	
@@ -160,10 +229,7 @@ func (b *Builder) BuildSelect() (string, []interface{}) {
 		builder.WriteString(" WHERE ")
 		whereConditions := make([]string, len(b.conditions))
 		for i, cond := range b.conditions {
-			whereConditions[i] = fmt.Sprintf("%s %s %s",
-				b.dialect.Quote(cond.Column),
-				cond.Operation,
-				b.dialect.Placeholder(i+1))
+			whereConditions[i] = b.renderCondition(cond, params)
 		}
 		builder.WriteString(strings.Join(whereConditions, " AND "))
 	}
@@ -178,12 +244,8 @@ func (b *Builder) BuildSelect() (string, []interface{}) {
 	if len(b.having) > 0 {
 		builder.WriteString(" HAVING ")
 		havingConditions := make([]string, len(b.having))
-		paramOffset := len(b.conditions)
 		for i, cond := range b.having {
-			havingConditions[i] = fmt.Sprintf("%s %s %s",
-				b.dialect.Quote(cond.Column),
-				cond.Operation,
-				b.dialect.Placeholder(paramOffset+i+1))
+			havingConditions[i] = b.renderCondition(cond, params)
 		}
 		builder.WriteString(strings.Join(havingConditions, " AND "))
 	}
@@ -204,5 +266,62 @@ func (b *Builder) BuildSelect() (string, []interface{}) {
 		builder.WriteString(fmt.Sprintf(" OFFSET %d", *b.offset))
 	}
 	
-	return builder.String(), b.params
+	// FOR UPDATE
+	if b.lock != "" {
+		builder.WriteString(" ")
+		builder.WriteString(b.lock)
+	}
+	
+	return builder.String()
+}
+
+// renderCondition renderiza uma condição e acumula seus parâmetros.
+// Valores do operador IN são expandidos em uma lista de placeholders.
+func (b *Builder) renderCondition(cond Condition, params *[]interface{}) string {
+	if cond.Operation == OpExists || cond.Operation == OpNotExists {
+		sub, ok := cond.Value.(*Builder)
+		if !ok {
+			return "1 = 0"
+		}
+		return fmt.Sprintf("%s (%s)", cond.Operation, sub.renderSelect(params))
+	}
+	
+	column := b.quoteColumn(cond.Column)
+	
+	if ref, ok := cond.Value.(ColumnRef); ok {
+		return fmt.Sprintf("%s %s %s", column, cond.Operation, b.quoteColumn(string(ref)))
+	}
+	
+	if cond.Operation == OpIsNull || cond.Operation == OpIsNotNull {
+		return fmt.Sprintf("%s %s", column, cond.Operation)
+	}
+	
+	if cond.Operation == OpIn {
+		values := reflect.ValueOf(cond.Value)
+		if values.Kind() == reflect.Slice || values.Kind() == reflect.Array {
+			// IN com lista vazia nunca é verdadeiro
+			if values.Len() == 0 {
+				return "1 = 0"
+			}
+			
+			placeholders := make([]string, values.Len())
+			for i := 0; i < values.Len(); i++ {
+				*params = append(*params, values.Index(i).Interface())
+				placeholders[i] = b.dialect.Placeholder(len(*params))
+			}
+			return fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ", "))
+		}
+	}
+	
+	*params = append(*params, cond.Value)
+	return fmt.Sprintf("%s %s %s", column, cond.Operation, b.dialect.Placeholder(len(*params)))
+}
+
+// quoteColumn coloca aspas em uma coluna, qualificada ou não ("tabela.coluna")
+func (b *Builder) quoteColumn(column string) string {
+	parts := strings.Split(column, ".")
+	for i, part := range parts {
+		parts[i] = b.dialect.Quote(part)
+	}
+	return strings.Join(parts, ".")
 }
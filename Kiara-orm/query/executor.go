@@ -5,13 +5,22 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
-	
-	"github.com/Flavio-coutinho/Kiara-orm/types"
+	"strings"
 )
 
+// JoinSeparator separa o campo de relacionamento da coluna nas colunas
+// de tabelas unidas por JOIN, por exemplo "User__name"
+const JoinSeparator = "__"
+
+// queryer abstrai *sql.DB e *sql.Tx para execução de consultas
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
 // Executor é responsável por executar queries SQL
 type Executor struct {
-	db      *sql.DB
+	db      queryer
 	builder *Builder
 }
 
@@ -23,6 +32,14 @@ func NewExecutor(db *sql.DB, builder *Builder) *Executor {
 	}
 }
 
+// NewExecutorTx cria um Executor que roda dentro de uma transação
+func NewExecutorTx(tx *sql.Tx, builder *Builder) *Executor {
+	return &Executor{
+		db:      tx,
+		builder: builder,
+	}
+}
+
 // QueryRow executa uma query e retorna uma única linha
 func (e *Executor) QueryRow(ctx context.Context, dest interface{}) error {
 	query, params := e.builder.BuildSelect()
@@ -31,19 +48,30 @@ func (e *Executor) QueryRow(ctx context.Context, dest interface{}) error {
 	return e.scanRow(row, dest)
 }
 
-// Query executa uma query e retorna múltiplas linhas
+// Query executa uma query e retorna múltiplas linhas.
+// O destino pode ser um ponteiro para slice ou para uma única struct.
 func (e *Executor) Query(ctx context.Context, dest interface{}) error {
-	query, params := e.builder.BuildSelect()
-	
-	rows, err := e.db.QueryContext(ctx, query, params...)
+	rows, err := e.Rows(ctx)
 	if err != nil {
-		return fmt.Errorf("erro ao executar query: %v", err)
+		return err
 	}
 	defer rows.Close()
 	
 	return e.scanRows(rows, dest)
 }
 
+// Rows executa a query e retorna as linhas sem fazer scan
+func (e *Executor) Rows(ctx context.Context) (*sql.Rows, error) {
+	query, params := e.builder.BuildSelect()
+	
+	rows, err := e.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		// %w preserva o erro do driver (SQLSTATE, código) para a classificação de retry
+		return nil, fmt.Errorf("erro ao executar query: %w", err)
+	}
+	return rows, nil
+}
+
 // scanRow faz o scan de uma única linha para uma struct
 func (e *Executor) scanRow(row *sql.Row, dest interface{}) error {
 	v := reflect.ValueOf(dest)
@@ -68,37 +96,192 @@ func (e *Executor) scanRow(row *sql.Row, dest interface{}) error {
 	return row.Scan(values...)
 }
 
-// scanRows faz o scan de múltiplas linhas para um slice de structs
+// scanRows faz o scan de múltiplas linhas para um slice de structs,
+// associando cada coluna retornada ao campo com a tag db correspondente
 func (e *Executor) scanRows(rows *sql.Rows, dest interface{}) error {
 	v := reflect.ValueOf(dest)
-	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
-		return fmt.Errorf("destino deve ser um ponteiro para slice")
+	if v.Kind() != reflect.Ptr {
+		return fmt.Errorf("destino deve ser um ponteiro")
+	}
+	
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	
+	// Destino único: faz o scan apenas da primeira linha
+	if v.Elem().Kind() == reflect.Struct {
+		if !rows.Next() {
+			if err := rows.Err(); err != nil {
+				return err
+			}
+			return sql.ErrNoRows
+		}
+		
+		return scanStruct(rows, v.Elem(), columns)
+	}
+	
+	if v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("destino deve ser um ponteiro para slice ou struct")
 	}
 	
 	sliceVal := v.Elem()
 	elemType := sliceVal.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
 	
 	for rows.Next() {
 		// Cria nova instância do tipo do elemento
-		elem := reflect.New(elemType).Elem()
+		elem := reflect.New(elemType)
+		
+		if err := scanStruct(rows, elem.Elem(), columns); err != nil {
+			return err
+		}
+		
+		if isPtr {
+			sliceVal.Set(reflect.Append(sliceVal, elem))
+		} else {
+			sliceVal.Set(reflect.Append(sliceVal, elem.Elem()))
+		}
+	}
+	
+	return rows.Err()
+}
+
+// joinedColumn guarda o valor de uma coluna de um relacionamento unido por JOIN
+type joinedColumn struct {
+	field  string        // Campo do relacionamento no modelo (ex.: User)
+	index  []int         // Índice do campo na struct relacionada
+	holder reflect.Value // Ponteiro para ponteiro: fica nil quando a coluna é NULL
+}
+
+// scanStruct faz o scan da linha atual em v, incluindo relacionamentos unidos por JOIN
+func scanStruct(rows *sql.Rows, v reflect.Value, columns []string) error {
+	values, joined := fieldPointers(v, columns)
+	if err := rows.Scan(values...); err != nil {
+		return fmt.Errorf("erro ao fazer scan da linha: %w", err)
+	}
+	
+	assignJoined(v, joined)
+	return nil
+}
+
+// fieldPointers retorna os endereços dos campos da struct na ordem das colunas.
+// Colunas de relacionamentos (Campo__coluna) são lidas em valores intermediários;
+// colunas sem campo correspondente são descartadas.
+func fieldPointers(v reflect.Value, columns []string) ([]interface{}, []joinedColumn) {
+	index := columnIndex(v.Type())
+	
+	values := make([]interface{}, len(columns))
+	var joined []joinedColumn
+	
+	for i, col := range columns {
+		if idx, ok := index[col]; ok {
+			values[i] = v.FieldByIndex(idx).Addr().Interface()
+			continue
+		}
 		
-		// Cria slice para armazenar os endereços dos campos
-		values := make([]interface{}, len(e.builder.columns))
-		for i, col := range e.builder.columns {
-			field, ok := elemType.FieldByName(col)
-			if !ok {
-				return fmt.Errorf("campo %s não encontrado na struct", col)
+		if parts := strings.SplitN(col, JoinSeparator, 2); len(parts) == 2 {
+			if idx, fieldType, ok := joinedField(v.Type(), parts[0], parts[1]); ok {
+				holder := reflect.New(reflect.PtrTo(fieldType))
+				joined = append(joined, joinedColumn{field: parts[0], index: idx, holder: holder})
+				values[i] = holder.Interface()
+				continue
 			}
-			
-			values[i] = elem.FieldByName(field.Name).Addr().Interface()
 		}
 		
-		if err := rows.Scan(values...); err != nil {
-			return fmt.Errorf("erro ao fazer scan da linha: %v", err)
+		values[i] = new(interface{})
+	}
+	return values, joined
+}
+
+// joinedField localiza a coluna na struct do relacionamento
+func joinedField(t reflect.Type, field, column string) ([]int, reflect.Type, bool) {
+	relField, ok := t.FieldByName(field)
+	if !ok {
+		return nil, nil, false
+	}
+	
+	relType := relField.Type
+	if relType.Kind() == reflect.Ptr {
+		relType = relType.Elem()
+	}
+	if relType.Kind() != reflect.Struct {
+		return nil, nil, false
+	}
+	
+	idx, ok := columnIndex(relType)[column]
+	if !ok {
+		return nil, nil, false
+	}
+	return idx, relType.FieldByIndex(idx).Type, true
+}
+
+// assignJoined preenche os relacionamentos lidos via JOIN. Quando todas as colunas
+// de um relacionamento são NULL (registro inexistente), o ponteiro fica nil.
+func assignJoined(v reflect.Value, joined []joinedColumn) {
+	present := make(map[string]bool)
+	for _, col := range joined {
+		if !col.holder.Elem().IsNil() {
+			present[col.field] = true
+		}
+	}
+	
+	initialized := make(map[string]bool)
+	for _, col := range joined {
+		target := v.FieldByName(col.field)
+		if !initialized[col.field] {
+			initialized[col.field] = true
+			target.Set(reflect.Zero(target.Type()))
+			if present[col.field] && target.Kind() == reflect.Ptr {
+				target.Set(reflect.New(target.Type().Elem()))
+			}
+		}
+		
+		if !present[col.field] {
+			continue
 		}
 		
-		sliceVal.Set(reflect.Append(sliceVal, elem))
+		target = reflect.Indirect(target)
+		
+		if value := col.holder.Elem(); !value.IsNil() {
+			target.FieldByIndex(col.index).Set(value.Elem())
+		}
 	}
+}
+
+// columnIndex mapeia nomes de coluna para os índices dos campos da struct,
+// seguindo a mesma convenção de nomes do schema.Parser
+func columnIndex(t reflect.Type) map[string][]int {
+	index := make(map[string][]int)
 	
-	return rows.Err()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		
+		tag := field.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+		
+		// Campos embutidos (ex.: softdelete.SoftDelete) contribuem com suas colunas
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && tag == "" {
+			for col, idx := range columnIndex(field.Type) {
+				index[col] = append([]int{i}, idx...)
+			}
+			continue
+		}
+		
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		index[name] = []int{i}
+	}
+	
+	return index
 }
@@ -3,6 +3,9 @@ package relation
 import (
 	"fmt"
 	"reflect"
+	"sync"
+	
+	"github.com/Flavio-coutinho/Kiara-orm/types"
 )
 
 // RelationType representa o tipo de relacionamento
@@ -12,65 +15,197 @@ const (
 	OneToOne RelationType = iota
 	OneToMany
 	ManyToMany
+	BelongsTo
 )
 
-// Relation representa um relacionamento entre modelos
+// Relation representa um relacionamento entre modelos.
+// ForeignKey, ReferenceKey e AssociationKey são nomes de campos Go;
+// JoinForeignKey e JoinReferenceKey são colunas da tabela de junção.
 type Relation struct {
 	Type         RelationType
 	Model        interface{}
-	ForeignKey   string
-	ReferenceKey string
+	ForeignKey   string // Em HasOne/HasMany fica no modelo relacionado; em BelongsTo, no próprio modelo
+	ReferenceKey string // Chave referenciada pela ForeignKey (padrão "ID")
 	JoinTable    string // Para Many-to-Many
 	Preload      bool
+	
+	// Para Many-to-Many
+	AssociationKey   string // Chave do modelo relacionado (padrão "ID")
+	JoinForeignKey   string // Coluna da junção que aponta para o modelo (padrão <tabela>_id)
+	JoinReferenceKey string // Coluna da junção que aponta para o relacionado (padrão <tabela>_id)
+	
+	// Para HasOne/HasMany polimórficos
+	PolymorphicType  string // Campo do relacionado que guarda o tipo do dono
+	PolymorphicValue string // Valor do tipo para este dono (padrão: nome da tabela do dono)
+}
+
+// IsPolymorphic indica se o relacionamento usa o par de colunas tipo + id
+func (r Relation) IsPolymorphic() bool {
+	return r.PolymorphicType != ""
+}
+
+// Option personaliza um relacionamento no momento do registro
+type Option func(*Relation)
+
+// WithReferenceKey define a chave referenciada pela chave estrangeira
+func WithReferenceKey(key string) Option {
+	return func(r *Relation) {
+		r.ReferenceKey = key
+	}
+}
+
+// WithAssociationKey define a chave do modelo relacionado em um Many-to-Many
+func WithAssociationKey(key string) Option {
+	return func(r *Relation) {
+		r.AssociationKey = key
+	}
+}
+
+// WithJoinKeys define as colunas da tabela de junção em um Many-to-Many
+func WithJoinKeys(foreignKey, referenceKey string) Option {
+	return func(r *Relation) {
+		r.JoinForeignKey = foreignKey
+		r.JoinReferenceKey = referenceKey
+	}
 }
 
+// WithPolymorphic torna um HasOne/HasMany polimórfico. typeField é o campo do modelo
+// relacionado que guarda o tipo do dono e value o valor gravado nele.
+func WithPolymorphic(typeField, value string) Option {
+	return func(r *Relation) {
+		r.PolymorphicType = typeField
+		r.PolymorphicValue = value
+	}
+}
+
+// MappingFunc retorna o mapeamento de tabela de um modelo
+type MappingFunc func(model interface{}) (*types.TableMapping, error)
+
 // RelationManager gerencia os relacionamentos entre modelos
 type RelationManager struct {
-	relations map[string]map[string]Relation // map[model][field]Relation
+	mu         sync.RWMutex
+	relations  map[string]map[string]Relation // map[model][field]Relation
+	mapper     MappingFunc
+	registered map[string]bool // Modelos cujas tags rel já foram registradas
 }
 
-// NewRelationManager cria uma nova instância do RelationManager
-func NewRelationManager() *RelationManager {
+// NewRelationManager cria uma nova instância do RelationManager.
+// O mapper é usado para validar os campos e colunas no registro.
+func NewRelationManager(mapper MappingFunc) *RelationManager {
 	return &RelationManager{
-		relations: make(map[string]map[string]Relation),
+		relations:  make(map[string]map[string]Relation),
+		mapper:     mapper,
+		registered: make(map[string]bool),
 	}
 }
 
-// HasOne define um relacionamento um-para-um
-func (rm *RelationManager) HasOne(model interface{}, field string, related interface{}, foreignKey string) {
-	rm.addRelation(model, field, Relation{
+// HasOne define um relacionamento um-para-um com a chave estrangeira no modelo relacionado
+func (rm *RelationManager) HasOne(model interface{}, field string, related interface{}, foreignKey string, opts ...Option) error {
+	return rm.register(model, field, Relation{
 		Type:         OneToOne,
 		Model:        related,
 		ForeignKey:   foreignKey,
 		ReferenceKey: "ID", // Assume ID como chave padrão
 		Preload:      false,
-	})
+	}, opts)
 }
 
 // HasMany define um relacionamento um-para-muitos
-func (rm *RelationManager) HasMany(model interface{}, field string, related interface{}, foreignKey string) {
-	rm.addRelation(model, field, Relation{
+func (rm *RelationManager) HasMany(model interface{}, field string, related interface{}, foreignKey string, opts ...Option) error {
+	return rm.register(model, field, Relation{
 		Type:         OneToMany,
 		Model:        related,
 		ForeignKey:   foreignKey,
 		ReferenceKey: "ID",
 		Preload:      false,
-	})
+	}, opts)
 }
 
-// ManyToMany define um relacionamento muitos-para-muitos
-func (rm *RelationManager) ManyToMany(model interface{}, field string, related interface{}, joinTable string) {
-	rm.addRelation(model, field, Relation{
-		Type:         ManyToMany,
+// BelongsTo define um relacionamento em que a chave estrangeira fica no próprio modelo
+// (ex.: Post.UserID referenciando User.ID)
+func (rm *RelationManager) BelongsTo(model interface{}, field string, related interface{}, foreignKey string, opts ...Option) error {
+	return rm.register(model, field, Relation{
+		Type:         BelongsTo,
 		Model:        related,
-		JoinTable:    joinTable,
+		ForeignKey:   foreignKey,
 		ReferenceKey: "ID",
 		Preload:      false,
-	})
+	}, opts)
+}
+
+// ManyToMany define um relacionamento muitos-para-muitos
+func (rm *RelationManager) ManyToMany(model interface{}, field string, related interface{}, joinTable string, opts ...Option) error {
+	return rm.register(model, field, Relation{
+		Type:           ManyToMany,
+		Model:          related,
+		JoinTable:      joinTable,
+		ReferenceKey:   "ID",
+		AssociationKey: "ID",
+		Preload:        false,
+	}, opts)
+}
+
+// RegisterMapping registra os relacionamentos declarados por tag no mapeamento do modelo.
+// Cada modelo é registrado uma única vez; chamadas seguintes não fazem nada.
+func (rm *RelationManager) RegisterMapping(model interface{}, mapping *types.TableMapping) error {
+	modelName := rm.getModelName(model)
+	
+	rm.mu.RLock()
+	done := rm.registered[modelName]
+	rm.mu.RUnlock()
+	if done {
+		return nil
+	}
+	
+	for _, rel := range mapping.Relations {
+		opts := make([]Option, 0)
+		if rel.ReferenceKey != "" {
+			opts = append(opts, WithReferenceKey(rel.ReferenceKey))
+		}
+		if rel.AssociationKey != "" {
+			opts = append(opts, WithAssociationKey(rel.AssociationKey))
+		}
+		if rel.JoinForeignKey != "" || rel.JoinReferenceKey != "" {
+			opts = append(opts, WithJoinKeys(rel.JoinForeignKey, rel.JoinReferenceKey))
+		}
+		if rel.PolymorphicType != "" {
+			opts = append(opts, WithPolymorphic(rel.PolymorphicType, rel.PolymorphicValue))
+		}
+		
+		var err error
+		switch rel.Kind {
+		case types.RelBelongsTo:
+			err = rm.BelongsTo(model, rel.FieldName, rel.Model, rel.ForeignKey, opts...)
+		case types.RelHasOne:
+			err = rm.HasOne(model, rel.FieldName, rel.Model, rel.ForeignKey, opts...)
+		case types.RelHasMany:
+			err = rm.HasMany(model, rel.FieldName, rel.Model, rel.ForeignKey, opts...)
+		case types.RelManyToMany:
+			err = rm.ManyToMany(model, rel.FieldName, rel.Model, rel.JoinTable, opts...)
+		default:
+			err = fmt.Errorf("tipo de relacionamento desconhecido: %s", rel.Kind)
+		}
+		if err != nil {
+			return err
+		}
+		
+		if rel.Preload {
+			rm.EnablePreload(model, rel.FieldName)
+		}
+	}
+	
+	rm.mu.Lock()
+	rm.registered[modelName] = true
+	rm.mu.Unlock()
+	
+	return nil
 }
 
 // EnablePreload habilita o carregamento automático de um relacionamento
 func (rm *RelationManager) EnablePreload(model interface{}, field string) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	
 	modelName := rm.getModelName(model)
 	if relations, ok := rm.relations[modelName]; ok {
 		if relation, ok := relations[field]; ok {
@@ -82,6 +217,9 @@ func (rm *RelationManager) EnablePreload(model interface{}, field string) {
 
 // GetRelation retorna um relacionamento específico
 func (rm *RelationManager) GetRelation(model interface{}, field string) (Relation, bool) {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	
 	modelName := rm.getModelName(model)
 	if relations, ok := rm.relations[modelName]; ok {
 		relation, ok := relations[field]
@@ -92,6 +230,9 @@ func (rm *RelationManager) GetRelation(model interface{}, field string) (Relatio
 
 // GetPreloadFields retorna todos os campos que devem ser pré-carregados
 func (rm *RelationManager) GetPreloadFields(model interface{}) []string {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	
 	modelName := rm.getModelName(model)
 	fields := make([]string, 0)
 	
@@ -106,8 +247,119 @@ func (rm *RelationManager) GetPreloadFields(model interface{}) []string {
 	return fields
 }
 
+// register aplica as opções, valida o relacionamento e o adiciona ao gerenciador
+func (rm *RelationManager) register(model interface{}, field string, relation Relation, opts []Option) error {
+	for _, opt := range opts {
+		opt(&relation)
+	}
+	
+	if err := rm.validate(model, field, &relation); err != nil {
+		return fmt.Errorf("relacionamento %s.%s inválido: %v", rm.getModelName(model), field, err)
+	}
+	
+	rm.addRelation(model, field, relation)
+	return nil
+}
+
+// validate verifica se o campo do relacionamento e as chaves existem nos
+// mapeamentos dos modelos. Chaves podem ser informadas pelo nome do campo Go
+// ou da coluna; em ambos os casos são normalizadas para o nome do campo.
+func (rm *RelationManager) validate(model interface{}, field string, relation *Relation) error {
+	modelType := reflect.TypeOf(model)
+	if modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	
+	target, ok := modelType.FieldByName(field)
+	if !ok {
+		return fmt.Errorf("campo %s não existe em %s", field, modelType.Name())
+	}
+	
+	many := relation.Type == OneToMany || relation.Type == ManyToMany
+	if many && target.Type.Kind() != reflect.Slice {
+		return fmt.Errorf("campo %s deve ser um slice", field)
+	}
+	if !many && target.Type.Kind() != reflect.Ptr && target.Type.Kind() != reflect.Struct {
+		return fmt.Errorf("campo %s deve ser um ponteiro ou struct", field)
+	}
+	
+	if rm.mapper == nil {
+		return nil
+	}
+	
+	owner, err := rm.mapper(model)
+	if err != nil {
+		return err
+	}
+	
+	related, err := rm.mapper(relation.Model)
+	if err != nil {
+		return err
+	}
+	
+	if relation.IsPolymorphic() && relation.Type != OneToOne && relation.Type != OneToMany {
+		return fmt.Errorf("apenas HasOne e HasMany podem ser polimórficos")
+	}
+	
+	switch relation.Type {
+	case OneToOne, OneToMany:
+		if relation.ReferenceKey, err = resolveField(owner, relation.ReferenceKey); err != nil {
+			return err
+		}
+		if relation.ForeignKey, err = resolveField(related, relation.ForeignKey); err != nil {
+			return err
+		}
+		if relation.IsPolymorphic() {
+			if relation.PolymorphicType, err = resolveField(related, relation.PolymorphicType); err != nil {
+				return err
+			}
+			if relation.PolymorphicValue == "" {
+				relation.PolymorphicValue = owner.TableName
+			}
+		}
+	case BelongsTo:
+		if relation.ForeignKey, err = resolveField(owner, relation.ForeignKey); err != nil {
+			return err
+		}
+		if relation.ReferenceKey, err = resolveField(related, relation.ReferenceKey); err != nil {
+			return err
+		}
+	case ManyToMany:
+		if relation.JoinTable == "" {
+			return fmt.Errorf("tabela de junção não informada")
+		}
+		if relation.ReferenceKey, err = resolveField(owner, relation.ReferenceKey); err != nil {
+			return err
+		}
+		if relation.AssociationKey, err = resolveField(related, relation.AssociationKey); err != nil {
+			return err
+		}
+		if relation.JoinForeignKey == "" {
+			relation.JoinForeignKey = owner.TableName + "_id"
+		}
+		if relation.JoinReferenceKey == "" {
+			relation.JoinReferenceKey = related.TableName + "_id"
+		}
+	}
+	
+	return nil
+}
+
+// resolveField encontra um campo pelo nome Go ou pelo nome da coluna e retorna o nome Go
+func resolveField(mapping *types.TableMapping, key string) (string, error) {
+	for _, field := range mapping.Fields {
+		if field.FieldName == key || field.Name == key {
+			return field.FieldName, nil
+		}
+	}
+	return "", fmt.Errorf("campo ou coluna %s não existe na tabela %s", key, mapping.TableName)
+}
+
 // addRelation adiciona um relacionamento ao gerenciador
 func (rm *RelationManager) addRelation(model interface{}, field string, relation Relation) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	
 	modelName := rm.getModelName(model)
 	if rm.relations[modelName] == nil {
 		rm.relations[modelName] = make(map[string]Relation)
@@ -0,0 +1,148 @@
+package schema
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	
+	"github.com/Flavio-coutinho/Kiara-orm/logger"
+)
+
+// ChecksumMode define a reação a migrações aplicadas que foram alteradas
+type ChecksumMode int
+
+const (
+	ChecksumFail   ChecksumMode = iota // MigrateUp retorna erro (padrão)
+	ChecksumWarn                       // Registra um aviso no logger e continua
+	ChecksumIgnore                     // Não verifica
+)
+
+// ChecksumMismatch é uma migração aplicada cujo conteúdo mudou
+type ChecksumMismatch struct {
+	Version int64
+	Name    string
+	Applied string // Checksum registrado ao aplicar
+	Current string // Checksum da migração registrada hoje
+}
+
+// DriftReport descreve as diferenças entre o banco e o esperado pela aplicação
+type DriftReport struct {
+	Changes    []Change           // Diferenças entre as tabelas e os mapeamentos
+	Migrations []ChecksumMismatch // Migrações alteradas depois de aplicadas
+}
+
+// HasDrift indica se alguma diferença foi encontrada
+func (r *DriftReport) HasDrift() bool {
+	return len(r.Changes) > 0 || len(r.Migrations) > 0
+}
+
+// String resume as diferenças, uma por linha
+func (r *DriftReport) String() string {
+	var lines []string
+	for _, change := range r.Changes {
+		lines = append(lines, change.Description)
+	}
+	for _, mismatch := range r.Migrations {
+		lines = append(lines, mismatch.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// String descreve a divergência
+func (c ChecksumMismatch) String() string {
+	return fmt.Sprintf("migração %d (%s) alterada após aplicada: checksum %s, esperado %s",
+		c.Version, c.Name, c.Current, c.Applied)
+}
+
+// SetLogger define o logger que recebe os avisos das migrações
+func (m *Migrator) SetLogger(logger logger.Logger) {
+	m.logger = logger
+}
+
+// VerifyChecksums compara o checksum das migrações aplicadas com o gravado ao
+// aplicá-las. Migrações de RegisterSQL e LoadSQLDir são sempre verificadas; as
+// migrações Go, só quando registradas com RegisterChecksum. Migrações Go de
+// Register e registros gravados antes da coluna existir não têm checksum e não
+// são comparados.
+func (m *Migrator) VerifyChecksums(ctx context.Context) ([]ChecksumMismatch, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	var mismatches []ChecksumMismatch
+	for _, migration := range m.migrations {
+		record, ok := applied[migration.Version]
+		if !ok || !checksumChanged(migration, record) {
+			continue
+		}
+		
+		mismatches = append(mismatches, ChecksumMismatch{
+			Version: migration.Version,
+			Name:    migration.Name,
+			Applied: record.checksum,
+			Current: migration.Checksum,
+		})
+	}
+	return mismatches, nil
+}
+
+// Verify compara o banco com os modelos e as migrações registradas, sem alterá-lo
+func (m *Migrator) Verify(ctx context.Context, models ...interface{}) (*DriftReport, error) {
+	plan, err := m.PlanAutoMigrate(ctx, models...)
+	if err != nil {
+		return nil, err
+	}
+	
+	mismatches, err := m.VerifyChecksums(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	return &DriftReport{Changes: plan.Changes, Migrations: mismatches}, nil
+}
+
+// checkChecksums aplica o ChecksumMode antes de executar migrações
+func (m *Migrator) checkChecksums(ctx context.Context) error {
+	if m.options.Checksums == ChecksumIgnore {
+		return nil
+	}
+	
+	mismatches, err := m.VerifyChecksums(ctx)
+	if err != nil || len(mismatches) == 0 {
+		return err
+	}
+	
+	if m.options.Checksums == ChecksumWarn {
+		for _, mismatch := range mismatches {
+			if m.logger != nil {
+				m.logger.Warn(ctx, "%s", mismatch)
+			}
+		}
+		return nil
+	}
+	
+	report := &DriftReport{Migrations: mismatches}
+	return fmt.Errorf("migrações aplicadas foram alteradas:\n%s", report)
+}
+
+// checksumChanged indica se o checksum atual difere do registrado. Só compara
+// quando os dois lados têm checksum.
+func checksumChanged(migration *VersionedMigration, record appliedVersion) bool {
+	return migration.Checksum != "" && record.checksum != "" && migration.Checksum != record.checksum
+}
+
+// checksum calcula o SHA-256 dos comandos das seções up e down
+func checksum(up, down []string) string {
+	hash := sha256.New()
+	for _, statement := range up {
+		hash.Write([]byte(statement + "\n"))
+	}
+	hash.Write([]byte(sqlDownMarker + "\n"))
+	for _, statement := range down {
+		hash.Write([]byte(statement + "\n"))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,88 @@
+package schema
+
+import (
+	"context"
+	
+	"github.com/Flavio-coutinho/Kiara-orm/dialect"
+	"github.com/Flavio-coutinho/Kiara-orm/types"
+)
+
+// sortByDependency ordena os mapeamentos para que as tabelas referenciadas por
+// chaves estrangeiras sejam criadas antes. Em ciclos, segue a ordem original.
+func sortByDependency(mappings []*TableMapping) []*TableMapping {
+	position := make(map[string]int, len(mappings))
+	for i, mapping := range mappings {
+		position[mapping.TableName] = i
+	}
+	
+	done := make([]bool, len(mappings))
+	ready := func(i int) bool {
+		for _, fk := range mappings[i].ForeignKeys {
+			if j, ok := position[fk.ReferenceTable]; ok && j != i && !done[j] {
+				return false
+			}
+		}
+		return true
+	}
+	
+	sorted := make([]*TableMapping, 0, len(mappings))
+	for len(sorted) < len(mappings) {
+		next := -1
+		for i := range mappings {
+			if !done[i] && ready(i) {
+				next = i
+				break
+			}
+		}
+		
+		// Ciclo: a primeira tabela pendente é criada e as chaves do ciclo vêm depois
+		if next < 0 {
+			for i := range mappings {
+				if !done[i] {
+					next = i
+					break
+				}
+			}
+		}
+		
+		done[next] = true
+		sorted = append(sorted, mappings[next])
+	}
+	return sorted
+}
+
+// deferForeignKeys separa as chaves estrangeiras que apontam para tabelas ainda
+// inexistentes que serão criadas depois no plano. O mapeamento retornado não as
+// contém; elas são adicionadas com ALTER TABLE após a criação de todas as tabelas.
+// O SQLite aceita referências a tabelas futuras e mantém todas na criação.
+func (m *Migrator) deferForeignKeys(ctx context.Context, mapping TableMapping, later []*TableMapping) (TableMapping, []types.ForeignKeyMapping, error) {
+	if _, ok := m.dialect.(*dialect.SQLite); ok {
+		return mapping, nil, nil
+	}
+	
+	pending := make(map[string]bool, len(later))
+	for _, other := range later {
+		pending[other.TableName] = true
+	}
+	
+	var kept, deferred []types.ForeignKeyMapping
+	for _, fk := range mapping.ForeignKeys {
+		if fk.ReferenceTable == mapping.TableName || !pending[fk.ReferenceTable] {
+			kept = append(kept, fk)
+			continue
+		}
+		
+		exists, err := m.tableExists(ctx, fk.ReferenceTable)
+		if err != nil {
+			return mapping, nil, err
+		}
+		if exists {
+			kept = append(kept, fk)
+		} else {
+			deferred = append(deferred, fk)
+		}
+	}
+	
+	mapping.ForeignKeys = kept
+	return mapping, deferred, nil
+}
@@ -0,0 +1,587 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	
+	"github.com/Flavio-coutinho/Kiara-orm/dialect"
+	"github.com/Flavio-coutinho/Kiara-orm/types"
+)
+
+// ChangeKind identifica o tipo de alteração de schema
+type ChangeKind string
+
+const (
+	ChangeCreateTable ChangeKind = "create_table"
+	ChangeAddColumn   ChangeKind = "add_column"
+	ChangeAlterColumn ChangeKind = "alter_column"
+	ChangeDropColumn  ChangeKind = "drop_column"
+	ChangeAddIndex    ChangeKind = "add_index"
+	ChangeDropIndex   ChangeKind = "drop_index"
+	ChangeRebuild     ChangeKind = "rebuild_table"
+	
+	ChangeAddForeignKey  ChangeKind = "add_foreign_key"
+	ChangeDropForeignKey ChangeKind = "drop_foreign_key"
+	ChangeAddCheck       ChangeKind = "add_check"
+	ChangeDropCheck      ChangeKind = "drop_check"
+)
+
+// Change é uma alteração entre o mapeamento e a tabela existente
+type Change struct {
+	Kind        ChangeKind
+	Table       string
+	Column      string
+	Index       string // Índice ou constraint afetado
+	Description string   // Resumo legível da alteração
+	Destructive bool     // Pode perder dados ou falhar com os dados existentes (remoções, redução de tipo, NOT NULL)
+	SQL         []string // Comandos que aplicam a alteração; vazio se o dialeto não a suporta
+	Skipped     bool     // Não será aplicada; o motivo fica em SkipReason
+	SkipReason  string
+	
+	cleanup []string // Comandos executados se a aplicação falhar no meio
+}
+
+// Name retorna o nome registrado na tabela de migrações
+func (c Change) Name() string {
+	parts := []string{string(c.Kind), c.Table}
+	if c.Column != "" {
+		parts = append(parts, c.Column)
+	}
+	if c.Index != "" {
+		parts = append(parts, c.Index)
+	}
+	return strings.Join(parts, "_")
+}
+
+// MigrateOptions configura o AutoMigrate e as migrações versionadas
+type MigrateOptions struct {
+	// AllowDestructive aplica alterações destrutivas; por padrão elas são ignoradas
+	AllowDestructive bool
+	
+	// LockTimeout é a espera máxima pelo lock das migrações; zero usa DefaultLockTimeout
+	LockTimeout time.Duration
+	
+	// Checksums define o que MigrateUp faz com migrações alteradas depois de aplicadas
+	Checksums ChecksumMode
+}
+
+// SetOptions define as opções das migrações
+func (m *Migrator) SetOptions(opts MigrateOptions) {
+	m.options = opts
+}
+
+// Diff compara o modelo com a tabela existente e retorna as alterações necessárias
+func (m *Migrator) Diff(ctx context.Context, model interface{}) ([]Change, error) {
+	mapping, err := m.parser.Parse(model)
+	if err != nil {
+		return nil, err
+	}
+	
+	return m.diffMapping(ctx, *mapping)
+}
+
+// diffMapping compara um mapeamento com a tabela existente
+func (m *Migrator) diffMapping(ctx context.Context, mapping TableMapping) ([]Change, error) {
+	exists, err := m.tableExists(ctx, mapping.TableName)
+	if err != nil {
+		return nil, err
+	}
+	
+	if !exists {
+		statements := []string{m.dialect.CreateTableSQL(mapping)}
+		for _, index := range mapping.Indexes {
+			statements = append(statements, m.dialect.IndexSQL(mapping.TableName, index))
+		}
+		statements = append(statements, m.commentSQL(mapping.TableName, mapping.Fields...)...)
+		
+		return []Change{{
+			Kind:        ChangeCreateTable,
+			Table:       mapping.TableName,
+			Description: fmt.Sprintf("criar tabela %s", mapping.TableName),
+			SQL:         statements,
+		}}, nil
+	}
+	
+	info, err := m.Introspect(ctx, mapping.TableName)
+	if err != nil {
+		return nil, err
+	}
+	
+	changes := m.diffTable(mapping, info)
+	
+	// O SQLite resolve o que não tem ALTER nativo recriando a tabela
+	if _, ok := m.dialect.(*dialect.SQLite); ok {
+		return m.planSQLiteRebuild(ctx, mapping, info, changes)
+	}
+	return changes, nil
+}
+
+// diffTable compara as colunas do mapeamento com as da tabela
+func (m *Migrator) diffTable(mapping TableMapping, info *TableInfo) []Change {
+	var changes []Change
+	table := mapping.TableName
+	
+	// Índices únicos declarados por tags não definem o UNIQUE da coluna
+	declared := make(map[string]bool, len(mapping.Indexes))
+	for _, index := range mapping.Indexes {
+		declared[index.Name] = true
+	}
+	
+	for _, field := range mapping.Fields {
+		column, ok := info.Column(field.Name)
+		if !ok {
+			changes = append(changes, m.addColumn(table, field))
+			continue
+		}
+		
+		changes = append(changes, m.diffColumn(table, field, column, info, declared)...)
+	}
+	
+	for _, column := range info.Columns {
+		if columnName(&mapping, column.Name) != "" {
+			continue
+		}
+		
+		changes = append(changes, Change{
+			Kind:        ChangeDropColumn,
+			Table:       table,
+			Column:      column.Name,
+			Description: fmt.Sprintf("remover coluna %s.%s", table, column.Name),
+			Destructive: true,
+			SQL:         []string{m.dialect.DropColumnSQL(table, column.Name)},
+		})
+	}
+	
+	changes = append(changes, m.diffIndexes(mapping, info)...)
+	return append(changes, m.diffForeignKeys(mapping, info)...)
+}
+
+// diffIndexes cria os índices declarados ausentes e recria os que mudaram.
+// Índices que não foram declarados por tags são preservados.
+func (m *Migrator) diffIndexes(mapping TableMapping, info *TableInfo) []Change {
+	var changes []Change
+	table := mapping.TableName
+	
+	for _, index := range mapping.Indexes {
+		existing, ok := info.Index(index.Name)
+		if ok && sameIndex(m.supportedIndex(index), existing) {
+			continue
+		}
+		
+		if ok {
+			changes = append(changes, Change{
+				Kind:        ChangeDropIndex,
+				Table:       table,
+				Index:       existing.Name,
+				Description: fmt.Sprintf("remover índice %s de %s(%s)", existing.Name, table, strings.Join(existing.Columns, ", ")),
+				SQL:         m.dropIndexSQL(table, existing),
+			})
+		}
+		
+		kind := "índice"
+		if index.Unique {
+			kind = "índice único"
+		}
+		changes = append(changes, Change{
+			Kind:        ChangeAddIndex,
+			Table:       table,
+			Index:       index.Name,
+			Description: fmt.Sprintf("adicionar %s %s em %s(%s)", kind, index.Name, table, strings.Join(index.Columns, ", ")),
+			SQL:         []string{m.dialect.IndexSQL(table, index)},
+		})
+	}
+	return changes
+}
+
+// supportedIndex remove do índice as opções que o dialeto ignora ao criá-lo
+func (m *Migrator) supportedIndex(index types.IndexMapping) types.IndexMapping {
+	switch m.dialect.(type) {
+	case *dialect.MySQL:
+		index.Where = ""
+		if method := strings.ToUpper(index.Method); method != "BTREE" && method != "HASH" {
+			index.Method = ""
+		}
+	case *dialect.SQLite:
+		index.Method = ""
+	}
+	return index
+}
+
+// sameIndex compara o índice declarado com o existente. A condição parcial só é
+// comparada pela presença, pois os bancos a reescrevem; o método, se declarado.
+func sameIndex(index types.IndexMapping, existing IndexInfo) bool {
+	if index.Unique != existing.Unique || (index.Where != "") != existing.Partial {
+		return false
+	}
+	if index.Method != "" && existing.Method != "" && !strings.EqualFold(index.Method, existing.Method) {
+		return false
+	}
+	
+	if len(index.Columns) != len(existing.Columns) {
+		return false
+	}
+	for i, column := range index.Columns {
+		if existing.Columns[i] != column {
+			return false
+		}
+	}
+	return true
+}
+
+// diffForeignKeys cria as chaves estrangeiras ausentes e recria as que mudaram.
+// Chaves criadas fora do mapeamento são preservadas.
+func (m *Migrator) diffForeignKeys(mapping TableMapping, info *TableInfo) []Change {
+	var changes []Change
+	table := mapping.TableName
+	
+	for _, fk := range mapping.ForeignKeys {
+		existing, ok := info.ForeignKey(fk.Column)
+		if ok && sameForeignKey(fk, existing) {
+			continue
+		}
+		
+		if ok {
+			var sql []string
+			if drop := m.dialect.DropForeignKeySQL(table, existing.Name); drop != "" {
+				sql = []string{drop}
+			}
+			changes = append(changes, Change{
+				Kind:        ChangeDropForeignKey,
+				Table:       table,
+				Column:      fk.Column,
+				Index:       existing.Name,
+				Description: fmt.Sprintf("remover chave estrangeira de %s.%s para %s(%s)", table, fk.Column, existing.ReferenceTable, existing.ReferenceColumn),
+				SQL:         sql,
+			})
+		}
+		changes = append(changes, m.addForeignKey(table, fk))
+	}
+	return changes
+}
+
+// addForeignKey adiciona a chave estrangeira a uma tabela existente
+func (m *Migrator) addForeignKey(table string, fk types.ForeignKeyMapping) Change {
+	change := Change{
+		Kind:        ChangeAddForeignKey,
+		Table:       table,
+		Column:      fk.Column,
+		Index:       fk.Name,
+		Description: fmt.Sprintf("adicionar chave estrangeira %s em %s.%s → %s(%s)", fk.Name, table, fk.Column, fk.ReferenceTable, fk.ReferenceColumn),
+	}
+	if sql := m.dialect.AddForeignKeySQL(table, fk); sql != "" {
+		change.SQL = []string{sql}
+	}
+	return change
+}
+
+// sameForeignKey compara a chave do mapeamento com a existente. Sem ação declarada,
+// o banco usa NO ACTION, equivalente a RESTRICT para a migração.
+func sameForeignKey(fk types.ForeignKeyMapping, existing ForeignKeyInfo) bool {
+	action := func(a string) string {
+		a = strings.ToUpper(a)
+		if a == "" || a == "RESTRICT" {
+			return "NO ACTION"
+		}
+		return a
+	}
+	
+	return fk.ReferenceTable == existing.ReferenceTable &&
+		fk.ReferenceColumn == existing.ReferenceColumn &&
+		action(fk.OnDelete) == action(existing.OnDelete) &&
+		action(fk.OnUpdate) == action(existing.OnUpdate)
+}
+
+// addColumn adiciona a coluna. A unicidade vira um índice separado, pois
+// alguns bancos (SQLite) não aceitam ADD COLUMN com UNIQUE.
+func (m *Migrator) addColumn(table string, field types.FieldMapping) Change {
+	change := Change{
+		Kind:        ChangeAddColumn,
+		Table:       table,
+		Column:      field.Name,
+		Description: fmt.Sprintf("adicionar coluna %s.%s %s", table, field.Name, m.dialect.GetDataTypeSQL(field)),
+	}
+	
+	unique := field.IsUnique
+	field.IsUnique = false
+	change.SQL = append([]string{m.dialect.AddColumnSQL(table, field)}, m.commentSQL(table, field)...)
+	
+	if unique {
+		name := uniqueIndexName(table, field.Name)
+		change.SQL = append(change.SQL, m.dialect.CreateIndexSQL(table, name, []string{field.Name}, true))
+	}
+	return change
+}
+
+// commentSQL gera os comandos que definem os comentários das colunas, nos dialetos
+// em que o comentário não faz parte da definição da coluna
+func (m *Migrator) commentSQL(table string, fields ...types.FieldMapping) []string {
+	var statements []string
+	for _, field := range fields {
+		if sql := m.dialect.CommentSQL(table, field); sql != "" {
+			statements = append(statements, sql)
+		}
+	}
+	return statements
+}
+
+// diffColumn compara tipo, nulidade, valor padrão, comentário, CHECK e unicidade de
+// uma coluna existente. Padrões, comentários e CHECKs não declarados são preservados:
+// remover o default do modelo não gera DROP DEFAULT, pois colunas autoincrementais
+// e de timestamp têm padrões que o modelo não declara. Use uma migração versionada.
+// Tornar a coluna NOT NULL é destrutivo: falha se houver linhas com NULL.
+func (m *Migrator) diffColumn(table string, field types.FieldMapping, column ColumnInfo, info *TableInfo, declared map[string]bool) []Change {
+	var changes []Change
+	
+	if column.Unique {
+		column.Unique = false
+		for _, index := range info.Indexes {
+			if index.Unique && !index.Primary && !declared[index.Name] && len(index.Columns) == 1 && index.Columns[0] == field.Name {
+				column.Unique = true
+			}
+		}
+	}
+	
+	wantType := m.dialect.GetDataTypeSQL(field)
+	typeName, size := parseColumnType(wantType)
+	typeChanged := typeName != column.Type || size != column.Size
+	nullChanged := field.IsNullable != column.Nullable && !column.PrimaryKey
+	defaultChanged := field.Default != "" && (column.Default == nil || normalizeDefault(field.Default) != normalizeDefault(*column.Default))
+	_, sqlite := m.dialect.(*dialect.SQLite)
+	commentChanged := field.Comment != "" && field.Comment != column.Comment && !sqlite
+	
+	if typeChanged || nullChanged || defaultChanged || commentChanged {
+		var details []string
+		if typeChanged {
+			details = append(details, fmt.Sprintf("tipo %s → %s", column.RawType, wantType))
+		}
+		if nullChanged {
+			details = append(details, nullability(column.Nullable)+" → "+nullability(field.IsNullable))
+		}
+		if defaultChanged {
+			details = append(details, "padrão "+field.Default)
+		}
+		if commentChanged {
+			details = append(details, "comentário")
+		}
+		
+		changes = append(changes, Change{
+			Kind:        ChangeAlterColumn,
+			Table:       table,
+			Column:      field.Name,
+			Description: fmt.Sprintf("alterar coluna %s.%s: %s", table, field.Name, strings.Join(details, ", ")),
+			Destructive: (typeChanged && narrows(column, typeName, size)) || (nullChanged && !field.IsNullable),
+			SQL:         append(m.dialect.AlterColumnSQL(table, field), m.commentSQL(table, field)...),
+		})
+	}
+	
+	changes = append(changes, m.diffCheck(table, field, info)...)
+	
+	if field.IsPrimaryKey || field.IsUnique == column.Unique {
+		return changes
+	}
+	
+	if field.IsUnique {
+		name := uniqueIndexName(table, field.Name)
+		return append(changes, Change{
+			Kind:        ChangeAddIndex,
+			Table:       table,
+			Index:       name,
+			Description: fmt.Sprintf("adicionar índice único %s em %s(%s)", name, table, field.Name),
+			SQL:         []string{m.dialect.CreateIndexSQL(table, name, []string{field.Name}, true)},
+		})
+	}
+	
+	for _, index := range info.Indexes {
+		if !index.Unique || index.Primary || declared[index.Name] || len(index.Columns) != 1 || index.Columns[0] != field.Name {
+			continue
+		}
+		changes = append(changes, Change{
+			Kind:        ChangeDropIndex,
+			Table:       table,
+			Index:       index.Name,
+			Description: fmt.Sprintf("remover índice único %s de %s(%s)", index.Name, table, field.Name),
+			SQL:         m.dropIndexSQL(table, index),
+		})
+	}
+	return changes
+}
+
+// diffCheck cria a constraint CHECK declarada na coluna, ou a recria quando a
+// expressão mudou. No SQLite as duas alterações viram a recriação da tabela.
+func (m *Migrator) diffCheck(table string, field types.FieldMapping, info *TableInfo) []Change {
+	if field.Check == "" {
+		return nil
+	}
+	
+	var changes []Change
+	name := dialect.CheckConstraintName(table, field.Name)
+	
+	if existing, ok := info.Check(name); ok {
+		if normalizeCheck(existing.Expression) == normalizeCheck(field.Check) {
+			return nil
+		}
+		
+		var sql []string
+		if drop := m.dialect.DropCheckSQL(table, name); drop != "" {
+			sql = []string{drop}
+		}
+		changes = append(changes, Change{
+			Kind:        ChangeDropCheck,
+			Table:       table,
+			Column:      field.Name,
+			Index:       name,
+			Description: fmt.Sprintf("remover CHECK %s de %s.%s: %s", name, table, field.Name, existing.Expression),
+			SQL:         sql,
+		})
+	}
+	
+	var sql []string
+	if add := m.dialect.AddCheckSQL(table, field); add != "" {
+		sql = []string{add}
+	}
+	return append(changes, Change{
+		Kind:        ChangeAddCheck,
+		Table:       table,
+		Column:      field.Name,
+		Index:       name,
+		Description: fmt.Sprintf("adicionar CHECK %s em %s.%s: %s", name, table, field.Name, field.Check),
+		SQL:         sql,
+	})
+}
+
+// dropIndexSQL remove um índice ou a constraint que o criou
+func (m *Migrator) dropIndexSQL(table string, index IndexInfo) []string {
+	if !index.Constraint {
+		return []string{m.dialect.DropIndexSQL(table, index.Name)}
+	}
+	
+	switch m.dialect.(type) {
+	case *dialect.PostgreSQL:
+		return []string{fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", m.dialect.Quote(table), m.dialect.Quote(index.Name))}
+	case *dialect.SQLite:
+		// Índices de constraints do SQLite só saem recriando a tabela
+		return nil
+	default:
+		return []string{m.dialect.DropIndexSQL(table, index.Name)}
+	}
+}
+
+// applyChanges executa as alterações e registra cada uma na tabela de migrações.
+// Alterações destrutivas só são aplicadas com AllowDestructive. Todos os comandos
+// usam a mesma conexão, pois PRAGMAs e BEGIN/COMMIT valem por conexão.
+func (m *Migrator) applyChanges(ctx context.Context, changes []Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	
+	for _, change := range changes {
+		if change.Skipped || m.skipReason(change) != "" {
+			continue
+		}
+		
+		for _, statement := range change.SQL {
+			if err := execStatement(ctx, conn, statement); err != nil {
+				for _, cleanup := range change.cleanup {
+					_, _ = conn.ExecContext(ctx, cleanup)
+				}
+				return fmt.Errorf("erro ao %s: %v", change.Description, err)
+			}
+		}
+		
+		if err := m.recordMigration(ctx, conn, change.Name()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipReason explica por que a alteração não será aplicada, ou retorna vazio
+func (m *Migrator) skipReason(change Change) string {
+	switch {
+	case change.Destructive && !m.options.AllowDestructive:
+		return "destrutiva, requer AllowDestructive"
+	case len(change.SQL) == 0:
+		return "não suportada pelo dialeto"
+	}
+	return ""
+}
+
+// narrows indica se a troca de tipo pode perder dados. São seguras apenas
+// o aumento de tamanho e as ampliações varchar → text e integer → bigint.
+func narrows(column ColumnInfo, typeName string, size int) bool {
+	switch {
+	case column.Type == typeName:
+		return size != 0 && (column.Size == 0 || size < column.Size)
+	case (column.Type == "varchar" || column.Type == "char") && typeName == "text":
+		return false
+	case column.Type == "integer" && typeName == "bigint":
+		return false
+	default:
+		return true
+	}
+}
+
+// defaultCast encontra o cast que o PostgreSQL acrescenta ao padrão ('ativo'::character varying)
+var defaultCast = regexp.MustCompile(`::[a-z ]+(\([0-9, ]*\))?$`)
+
+// normalizeDefault reduz a expressão padrão à forma comparável entre o declarado e o
+// que o banco devolve: sem cast, parênteses externos e aspas, em minúsculas
+func normalizeDefault(value string) string {
+	value = strings.TrimSpace(value)
+	for {
+		trimmed := defaultCast.ReplaceAllString(value, "")
+		if strings.HasPrefix(trimmed, "(") && strings.HasSuffix(trimmed, ")") {
+			trimmed = trimmed[1 : len(trimmed)-1]
+		}
+		if trimmed == value {
+			break
+		}
+		value = trimmed
+	}
+	
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		value = strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+	return strings.ToLower(value)
+}
+
+var (
+	// checkCast encontra os casts que o PostgreSQL acrescenta às expressões
+	checkCast = regexp.MustCompile(`::(character varying|double precision|timestamp with(out)? time zone|[a-z_0-9]+)(\[\])?`)
+	
+	// checkIntroducer encontra os charsets que o MySQL prefixa aos textos (_utf8mb4'a')
+	checkIntroducer = regexp.MustCompile(`_[a-z0-9]+'`)
+)
+
+// normalizeCheck reduz a expressão do CHECK a uma forma comparável entre a declarada
+// e a devolvida pelo banco: sem casts, charsets, aspas de identificadores, parênteses
+// e espaços, e com o "= ANY (ARRAY[...])" do PostgreSQL de volta a IN
+func normalizeCheck(expression string) string {
+	expression = strings.ToLower(expression)
+	expression = checkCast.ReplaceAllString(expression, "")
+	expression = checkIntroducer.ReplaceAllString(expression, "'")
+	expression = strings.NewReplacer("(", "", ")", "", " ", "", "\t", "", "\n", "", `"`, "", "`", "").Replace(expression)
+	return strings.NewReplacer("=anyarray[", "in", "]", "").Replace(expression)
+}
+
+// uniqueIndexName retorna o nome do índice único criado para uma coluna
+func uniqueIndexName(table, column string) string {
+	return fmt.Sprintf("uq_%s_%s", table, column)
+}
+
+// nullability descreve a nulidade de uma coluna
+func nullability(nullable bool) string {
+	if nullable {
+		return "NULL"
+	}
+	return "NOT NULL"
+}
@@ -0,0 +1,470 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	
+	"github.com/Flavio-coutinho/Kiara-orm/dialect"
+)
+
+// ColumnInfo descreve uma coluna existente no banco
+type ColumnInfo struct {
+	Name       string
+	Type       string  // Tipo normalizado (ex.: varchar, integer, timestamp)
+	RawType    string  // Tipo como informado pelo banco
+	Size       int     // Tamanho de tipos como varchar (0 se não se aplica)
+	Nullable   bool
+	Default    *string // Expressão padrão, nil se não houver
+	Unique     bool    // Possui índice único próprio (exceto a chave primária)
+	PrimaryKey bool
+	Comment    string
+}
+
+// IndexInfo descreve um índice existente no banco
+type IndexInfo struct {
+	Name       string
+	Columns    []string
+	Unique     bool
+	Primary    bool
+	Constraint bool   // Criado por uma constraint (UNIQUE/PRIMARY KEY) e não por CREATE INDEX
+	Method     string // Método de acesso (vazio no SQLite)
+	Partial    bool   // Possui condição WHERE
+}
+
+// ForeignKeyInfo descreve uma chave estrangeira existente no banco
+type ForeignKeyInfo struct {
+	Name            string // Vazio no SQLite, que não expõe o nome da constraint
+	Column          string
+	ReferenceTable  string
+	ReferenceColumn string
+	OnDelete        string
+	OnUpdate        string
+}
+
+// TableInfo descreve a estrutura atual de uma tabela
+type TableInfo struct {
+	Name        string
+	Columns     []ColumnInfo
+	Indexes     []IndexInfo
+	ForeignKeys []ForeignKeyInfo
+	Checks      []CheckInfo
+}
+
+// CheckInfo descreve uma constraint CHECK existente. A expressão vem como o banco
+// a devolve, que pode reescrevê-la (casts, parênteses, IN como = ANY).
+type CheckInfo struct {
+	Name       string
+	Expression string
+}
+
+// Column retorna a coluna com o nome informado
+func (t *TableInfo) Column(name string) (ColumnInfo, bool) {
+	for _, column := range t.Columns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+	return ColumnInfo{}, false
+}
+
+// Index retorna o índice com o nome informado
+func (t *TableInfo) Index(name string) (IndexInfo, bool) {
+	for _, index := range t.Indexes {
+		if index.Name == name {
+			return index, true
+		}
+	}
+	return IndexInfo{}, false
+}
+
+// ForeignKey retorna a chave estrangeira da coluna informada
+func (t *TableInfo) ForeignKey(column string) (ForeignKeyInfo, bool) {
+	for _, fk := range t.ForeignKeys {
+		if fk.Column == column {
+			return fk, true
+		}
+	}
+	return ForeignKeyInfo{}, false
+}
+
+// Check retorna a constraint CHECK com o nome informado
+func (t *TableInfo) Check(name string) (CheckInfo, bool) {
+	for _, check := range t.Checks {
+		if check.Name == name {
+			return check, true
+		}
+	}
+	return CheckInfo{}, false
+}
+
+// Introspect lê colunas, índices e constraints de uma tabela existente
+func (m *Migrator) Introspect(ctx context.Context, table string) (*TableInfo, error) {
+	info := &TableInfo{Name: table}
+	
+	columns, err := m.introspectColumns(ctx, table)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler colunas de %s: %v", table, err)
+	}
+	
+	indexes, err := m.introspectIndexes(ctx, table)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler índices de %s: %v", table, err)
+	}
+	
+	foreignKeys, err := m.introspectForeignKeys(ctx, table)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler chaves estrangeiras de %s: %v", table, err)
+	}
+	
+	checks, err := m.introspectChecks(ctx, table)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler constraints de %s: %v", table, err)
+	}
+	
+	// Índices de uma coluna definem UNIQUE e PRIMARY KEY da coluna
+	for i := range columns {
+		for _, index := range indexes {
+			if len(index.Columns) != 1 || index.Columns[0] != columns[i].Name {
+				continue
+			}
+			if index.Primary {
+				columns[i].PrimaryKey = true
+			} else if index.Unique {
+				columns[i].Unique = true
+			}
+		}
+	}
+	
+	info.Columns = columns
+	info.Indexes = indexes
+	info.ForeignKeys = foreignKeys
+	info.Checks = checks
+	return info, nil
+}
+
+// introspectColumns lê as colunas da tabela
+func (m *Migrator) introspectColumns(ctx context.Context, table string) ([]ColumnInfo, error) {
+	var query string
+	switch m.dialect.(type) {
+	case *dialect.PostgreSQL:
+		query = `
+			SELECT column_name, data_type, COALESCE(character_maximum_length, 0),
+				is_nullable = 'YES', column_default, FALSE,
+				COALESCE(col_description(format('%I.%I', table_schema, table_name)::regclass, ordinal_position), '')
+			FROM information_schema.columns
+			WHERE table_schema = current_schema()
+			AND table_name = $1
+			ORDER BY ordinal_position
+		`
+	case *dialect.MySQL:
+		query = `
+			SELECT column_name, data_type, COALESCE(character_maximum_length, 0),
+				is_nullable = 'YES', column_default, column_key = 'PRI', column_comment
+			FROM information_schema.columns
+			WHERE table_schema = DATABASE()
+			AND table_name = ?
+			ORDER BY ordinal_position
+		`
+	case *dialect.SQLite:
+		query = `
+			SELECT name, type, 0, "notnull" = 0, dflt_value, pk > 0, ''
+			FROM pragma_table_info(?)
+			ORDER BY cid
+		`
+	default:
+		return nil, fmt.Errorf("dialeto não suportado")
+	}
+	
+	rows, err := m.db.QueryContext(ctx, query, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var columns []ColumnInfo
+	for rows.Next() {
+		var column ColumnInfo
+		var size int64
+		if err := rows.Scan(&column.Name, &column.RawType, &size, &column.Nullable, &column.Default,
+			&column.PrimaryKey, &column.Comment); err != nil {
+			return nil, err
+		}
+		
+		column.Type, column.Size = parseColumnType(column.RawType)
+		if column.Size == 0 && hasSize(column.Type) {
+			column.Size = int(size)
+		}
+		columns = append(columns, column)
+	}
+	
+	return columns, rows.Err()
+}
+
+// introspectIndexes lê os índices da tabela com as colunas em ordem
+func (m *Migrator) introspectIndexes(ctx context.Context, table string) ([]IndexInfo, error) {
+	var query string
+	switch m.dialect.(type) {
+	case *dialect.PostgreSQL:
+		query = `
+			SELECT i.relname, ix.indisunique, ix.indisprimary, c.conname IS NOT NULL,
+				am.amname, ix.indpred IS NOT NULL, a.attname
+			FROM pg_index ix
+			JOIN pg_class t ON t.oid = ix.indrelid
+			JOIN pg_class i ON i.oid = ix.indexrelid
+			JOIN pg_am am ON am.oid = i.relam
+			JOIN pg_namespace n ON n.oid = t.relnamespace
+			JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k(attnum, ord) ON TRUE
+			JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+			LEFT JOIN pg_constraint c ON c.conindid = ix.indexrelid AND c.contype IN ('p', 'u')
+			WHERE n.nspname = current_schema()
+			AND t.relname = $1
+			ORDER BY i.relname, k.ord
+		`
+	case *dialect.MySQL:
+		query = `
+			SELECT index_name, non_unique = 0, index_name = 'PRIMARY', FALSE,
+				index_type, FALSE, column_name
+			FROM information_schema.statistics
+			WHERE table_schema = DATABASE()
+			AND table_name = ?
+			ORDER BY index_name, seq_in_index
+		`
+	case *dialect.SQLite:
+		query = `
+			SELECT il.name, il."unique", il.origin = 'pk', il.origin <> 'c',
+				'', il.partial, ii.name
+			FROM pragma_index_list(?) il
+			JOIN pragma_index_info(il.name) ii
+			ORDER BY il.name, ii.seqno
+		`
+	default:
+		return nil, fmt.Errorf("dialeto não suportado")
+	}
+	
+	rows, err := m.db.QueryContext(ctx, query, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var indexes []IndexInfo
+	for rows.Next() {
+		var index IndexInfo
+		var column string
+		if err := rows.Scan(&index.Name, &index.Unique, &index.Primary, &index.Constraint,
+			&index.Method, &index.Partial, &column); err != nil {
+			return nil, err
+		}
+		
+		// As linhas de um mesmo índice vêm em sequência, uma por coluna
+		if n := len(indexes); n > 0 && indexes[n-1].Name == index.Name {
+			indexes[n-1].Columns = append(indexes[n-1].Columns, column)
+			continue
+		}
+		
+		index.Columns = []string{column}
+		indexes = append(indexes, index)
+	}
+	
+	return indexes, rows.Err()
+}
+
+// introspectForeignKeys lê as chaves estrangeiras da tabela
+func (m *Migrator) introspectForeignKeys(ctx context.Context, table string) ([]ForeignKeyInfo, error) {
+	var query string
+	switch m.dialect.(type) {
+	case *dialect.PostgreSQL:
+		query = `
+			SELECT c.conname, a.attname, rt.relname, ra.attname,
+				CASE c.confdeltype WHEN 'c' THEN 'CASCADE' WHEN 'n' THEN 'SET NULL'
+					WHEN 'd' THEN 'SET DEFAULT' WHEN 'r' THEN 'RESTRICT' ELSE 'NO ACTION' END,
+				CASE c.confupdtype WHEN 'c' THEN 'CASCADE' WHEN 'n' THEN 'SET NULL'
+					WHEN 'd' THEN 'SET DEFAULT' WHEN 'r' THEN 'RESTRICT' ELSE 'NO ACTION' END
+			FROM pg_constraint c
+			JOIN pg_class t ON t.oid = c.conrelid
+			JOIN pg_namespace n ON n.oid = t.relnamespace
+			JOIN pg_class rt ON rt.oid = c.confrelid
+			JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = c.conkey[1]
+			JOIN pg_attribute ra ON ra.attrelid = c.confrelid AND ra.attnum = c.confkey[1]
+			WHERE c.contype = 'f'
+			AND n.nspname = current_schema()
+			AND t.relname = $1
+			ORDER BY c.conname
+		`
+	case *dialect.MySQL:
+		query = `
+			SELECT k.constraint_name, k.column_name, k.referenced_table_name, k.referenced_column_name,
+				r.delete_rule, r.update_rule
+			FROM information_schema.key_column_usage k
+			JOIN information_schema.referential_constraints r
+				ON r.constraint_schema = k.constraint_schema AND r.constraint_name = k.constraint_name
+			WHERE k.table_schema = DATABASE()
+			AND k.table_name = ?
+			AND k.referenced_table_name IS NOT NULL
+			ORDER BY k.constraint_name, k.ordinal_position
+		`
+	case *dialect.SQLite:
+		query = `
+			SELECT '', "from", "table", COALESCE("to", ''), on_delete, on_update
+			FROM pragma_foreign_key_list(?)
+			ORDER BY id, seq
+		`
+	default:
+		return nil, fmt.Errorf("dialeto não suportado")
+	}
+	
+	rows, err := m.db.QueryContext(ctx, query, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var foreignKeys []ForeignKeyInfo
+	for rows.Next() {
+		var fk ForeignKeyInfo
+		if err := rows.Scan(&fk.Name, &fk.Column, &fk.ReferenceTable, &fk.ReferenceColumn, &fk.OnDelete, &fk.OnUpdate); err != nil {
+			return nil, err
+		}
+		foreignKeys = append(foreignKeys, fk)
+	}
+	
+	return foreignKeys, rows.Err()
+}
+
+// sqliteCheckPattern encontra as constraints CHECK nomeadas no CREATE TABLE do SQLite
+var sqliteCheckPattern = regexp.MustCompile(`(?i)CONSTRAINT\s+"?([^"\s]+)"?\s+CHECK\s*\(`)
+
+// introspectChecks lê as constraints CHECK da tabela e suas expressões
+func (m *Migrator) introspectChecks(ctx context.Context, table string) ([]CheckInfo, error) {
+	var query string
+	switch m.dialect.(type) {
+	case *dialect.PostgreSQL:
+		query = `
+			SELECT c.conname, pg_get_constraintdef(c.oid)
+			FROM pg_constraint c
+			JOIN pg_class t ON t.oid = c.conrelid
+			JOIN pg_namespace n ON n.oid = t.relnamespace
+			WHERE c.contype = 'c'
+			AND n.nspname = current_schema()
+			AND t.relname = $1
+			ORDER BY c.conname
+		`
+	case *dialect.MySQL:
+		query = `
+			SELECT tc.constraint_name, cc.check_clause
+			FROM information_schema.table_constraints tc
+			JOIN information_schema.check_constraints cc
+				ON cc.constraint_schema = tc.constraint_schema
+				AND cc.constraint_name = tc.constraint_name
+			WHERE tc.table_schema = DATABASE()
+			AND tc.table_name = ?
+			AND tc.constraint_type = 'CHECK'
+			ORDER BY tc.constraint_name
+		`
+	case *dialect.SQLite:
+		// O SQLite não lista constraints: nomes e expressões vêm do CREATE TABLE
+		var createSQL string
+		err := m.db.QueryRowContext(ctx, "SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&createSQL)
+		if err != nil {
+			return nil, err
+		}
+		
+		var checks []CheckInfo
+		for _, match := range sqliteCheckPattern.FindAllStringSubmatchIndex(createSQL, -1) {
+			checks = append(checks, CheckInfo{
+				Name:       createSQL[match[2]:match[3]],
+				Expression: parenthesized(createSQL[match[1]-1:]),
+			})
+		}
+		return checks, nil
+	default:
+		return nil, fmt.Errorf("dialeto não suportado")
+	}
+	
+	rows, err := m.db.QueryContext(ctx, query, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var checks []CheckInfo
+	for rows.Next() {
+		var check CheckInfo
+		if err := rows.Scan(&check.Name, &check.Expression); err != nil {
+			return nil, err
+		}
+		
+		// pg_get_constraintdef retorna "CHECK (expressão)"
+		check.Expression = strings.TrimSpace(strings.TrimPrefix(check.Expression, "CHECK"))
+		checks = append(checks, check)
+	}
+	
+	return checks, rows.Err()
+}
+
+// parenthesized retorna o conteúdo do parêntese que abre o texto, respeitando
+// parênteses aninhados e textos entre aspas simples
+func parenthesized(text string) string {
+	depth, quoted := 0, false
+	for i, r := range text {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case quoted:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth == 0 {
+				return text[1:i]
+			}
+		}
+	}
+	return text
+}
+
+// typeAliases normaliza os nomes de tipo informados pelos bancos e gerados pelos dialetos
+var typeAliases = map[string]string{
+	"int":                         "integer",
+	"int4":                        "integer",
+	"serial":                      "integer",
+	"int8":                        "bigint",
+	"bigserial":                   "bigint",
+	"bool":                        "boolean",
+	"tinyint":                     "boolean",
+	"double precision":            "double",
+	"float8":                      "double",
+	"character varying":           "varchar",
+	"character":                   "char",
+	"timestamp without time zone": "timestamp",
+	"time without time zone":      "time",
+}
+
+// parseColumnType separa um tipo como VARCHAR(255) no nome normalizado e no tamanho
+func parseColumnType(sqlType string) (string, int) {
+	name := strings.ToLower(strings.TrimSpace(sqlType))
+	size := 0
+	
+	if open := strings.Index(name, "("); open >= 0 {
+		if close := strings.Index(name[open:], ")"); close > 0 {
+			size, _ = strconv.Atoi(strings.TrimSpace(name[open+1 : open+close]))
+		}
+		name = strings.TrimSpace(name[:open])
+	}
+	
+	if alias, ok := typeAliases[name]; ok {
+		name = alias
+	}
+	if !hasSize(name) {
+		size = 0
+	}
+	return name, size
+}
+
+// hasSize indica se o tamanho faz parte do tipo
+func hasSize(typeName string) bool {
+	return typeName == "varchar" || typeName == "char"
+}
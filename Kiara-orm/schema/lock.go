@@ -0,0 +1,224 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sync"
+	"time"
+	
+	"github.com/Flavio-coutinho/Kiara-orm/dialect"
+)
+
+const (
+	// MigrationLockName identifica o lock das migrações no banco
+	MigrationLockName = "kiara_schema_migrations"
+	
+	// LockTable guarda o lock das migrações no SQLite, que não tem locks nomeados
+	LockTable = "schema_migrations_lock"
+	
+	// DefaultLockTimeout é a espera máxima pelo lock quando MigrateOptions.LockTimeout é zero
+	DefaultLockTimeout = time.Minute
+	
+	lockPollInterval = 100 * time.Millisecond
+)
+
+// lockToken identifica a execução que detém o lock das migrações
+type lockToken struct{}
+
+// lockContextKey guarda no contexto o lockToken da execução em andamento
+type lockContextKey struct{}
+
+// migrationLock registra qual execução do Migrator detém o lock
+type migrationLock struct {
+	mu     sync.Mutex
+	holder *lockToken
+}
+
+// held indica se o contexto pertence à execução que detém o lock
+func (l *migrationLock) held(ctx context.Context) bool {
+	token, ok := ctx.Value(lockContextKey{}).(*lockToken)
+	
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return ok && token == l.holder
+}
+
+// set define a execução que detém o lock; nil o libera
+func (l *migrationLock) set(token *lockToken) {
+	l.mu.Lock()
+	l.holder = token
+	l.mu.Unlock()
+}
+
+// withLock executa fn segurando o lock das migrações, para que réplicas iniciando
+// ao mesmo tempo não executem migrações concorrentes. Usa pg_advisory_lock no
+// PostgreSQL, GET_LOCK no MySQL e uma linha em LockTable no SQLite. As tabelas de
+// controle (migrations, schema_migrations) devem ser criadas dentro de fn.
+//
+// O lock é reentrante: fn recebe um contexto que o identifica, e chamadas aninhadas
+// com esse contexto (um AutoMigrate dentro de uma migração Go) não o obtêm de novo.
+func (m *Migrator) withLock(ctx context.Context, fn func(ctx context.Context) error) error {
+	if m.lock.held(ctx) {
+		return fn(ctx)
+	}
+	
+	timeout := m.options.LockTimeout
+	if timeout <= 0 {
+		timeout = DefaultLockTimeout
+	}
+	
+	lockCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	
+	unlock, err := m.acquire(lockCtx, timeout)
+	if err != nil {
+		if lockCtx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("tempo esgotado após %v aguardando o lock das migrações", timeout)
+		}
+		return fmt.Errorf("erro ao obter lock das migrações: %w", err)
+	}
+	defer unlock()
+	
+	token := &lockToken{}
+	m.lock.set(token)
+	defer m.lock.set(nil)
+	
+	return fn(context.WithValue(ctx, lockContextKey{}, token))
+}
+
+// acquire obtém o lock no banco e retorna a função que o libera
+func (m *Migrator) acquire(ctx context.Context, timeout time.Duration) (func(), error) {
+	switch m.dialect.(type) {
+	case *dialect.PostgreSQL:
+		return m.sessionLock(ctx, func(conn *sql.Conn) (bool, error) {
+			var locked bool
+			err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", advisoryLockKey()).Scan(&locked)
+			return locked, err
+		}, "SELECT pg_advisory_unlock($1)", advisoryLockKey())
+	case *dialect.MySQL:
+		seconds := int(timeout / time.Second)
+		return m.sessionLock(ctx, func(conn *sql.Conn) (bool, error) {
+			var locked sql.NullInt64
+			err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", MigrationLockName, seconds).Scan(&locked)
+			return locked.Valid && locked.Int64 == 1, err
+		}, "SELECT RELEASE_LOCK(?)", MigrationLockName)
+	case *dialect.SQLite:
+		return m.rowLock(ctx)
+	default:
+		return nil, fmt.Errorf("dialeto não suportado")
+	}
+}
+
+// sessionLock tenta obter um lock de sessão até conseguir ou o contexto expirar.
+// A conexão fica reservada enquanto o lock é mantido, pois ele pertence à sessão.
+func (m *Migrator) sessionLock(ctx context.Context, try func(conn *sql.Conn) (bool, error), release string, args ...interface{}) (func(), error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	for {
+		locked, err := try(conn)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if locked {
+			break
+		}
+		
+		if err := sleep(ctx, lockPollInterval); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	
+	return func() {
+		_, _ = conn.ExecContext(context.Background(), release, args...)
+		conn.Close()
+	}, nil
+}
+
+// rowLock insere a linha de lock no SQLite, aguardando enquanto outro processo a detém.
+// Um processo encerrado sem liberar o lock deixa a linha; use ForceUnlock nesse caso.
+func (m *Migrator) rowLock(ctx context.Context) (func(), error) {
+	create := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY,
+			owner VARCHAR(255) NOT NULL,
+			locked_at TIMESTAMP NOT NULL
+		)
+	`, m.dialect.Quote(LockTable))
+	if _, err := m.db.ExecContext(ctx, create); err != nil {
+		return nil, err
+	}
+	
+	owner := lockOwner()
+	insert := fmt.Sprintf("INSERT OR IGNORE INTO %s (id, owner, locked_at) VALUES (1, ?, ?)", m.dialect.Quote(LockTable))
+	
+	for {
+		result, err := m.db.ExecContext(ctx, insert, owner, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if affected, err := result.RowsAffected(); err != nil {
+			return nil, err
+		} else if affected == 1 {
+			break
+		}
+		
+		if err := sleep(ctx, lockPollInterval); err != nil {
+			return nil, err
+		}
+	}
+	
+	release := fmt.Sprintf("DELETE FROM %s WHERE id = 1 AND owner = ?", m.dialect.Quote(LockTable))
+	return func() {
+		_, _ = m.db.ExecContext(context.Background(), release, owner)
+	}, nil
+}
+
+// ForceUnlock remove o lock das migrações deixado por um processo encerrado.
+// Só é necessário no SQLite; nos demais dialetos o lock termina com a conexão.
+func (m *Migrator) ForceUnlock(ctx context.Context) error {
+	if _, ok := m.dialect.(*dialect.SQLite); !ok {
+		return nil
+	}
+	
+	exists, err := m.tableExists(ctx, LockTable)
+	if err != nil || !exists {
+		return err
+	}
+	
+	_, err = m.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", m.dialect.Quote(LockTable)))
+	return err
+}
+
+// advisoryLockKey converte o nome do lock na chave numérica do pg_advisory_lock
+func advisoryLockKey() int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(MigrationLockName))
+	return int64(hash.Sum64())
+}
+
+// lockOwner identifica o processo que detém o lock
+func lockOwner() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s:%d:%d", host, os.Getpid(), time.Now().UnixNano())
+}
+
+// sleep aguarda o intervalo ou o cancelamento do contexto
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
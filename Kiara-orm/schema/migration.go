@@ -7,6 +7,7 @@ import (
 	"time"
 	
 	"github.com/Flavio-coutinho/Kiara-orm/dialect"
+	"github.com/Flavio-coutinho/Kiara-orm/logger"
 )
 
 // Migration representa uma migração do banco de dados
@@ -22,6 +23,11 @@ type Migrator struct {
 	db      *sql.DB
 	dialect dialect.Dialect
 	parser  *Parser
+	
+	migrations []*VersionedMigration // Migrações versionadas registradas
+	options    MigrateOptions
+	logger     logger.Logger
+	lock       migrationLock
 }
 
 // NewMigrator cria uma nova instância do Migrator
@@ -50,66 +56,104 @@ func (m *Migrator) ensureMigrationTable(ctx context.Context) error {
 
 // AutoMigrate cria ou atualiza tabelas baseado nas structs
 func (m *Migrator) AutoMigrate(ctx context.Context, models ...interface{}) error {
-	if err := m.ensureMigrationTable(ctx); err != nil {
-		return fmt.Errorf("erro ao criar tabela de migrações: %v", err)
-	}
-	
-	for _, model := range models {
-		if err := m.migrateModel(ctx, model); err != nil {
+	// A tabela de controle também é criada sob o lock: réplicas concorrentes no
+	// CREATE TABLE IF NOT EXISTS falham no PostgreSQL (violação única em pg_type)
+	return m.withLock(ctx, func(ctx context.Context) error {
+		if err := m.ensureMigrationTable(ctx); err != nil {
+			return fmt.Errorf("erro ao criar tabela de migrações: %w", err)
+		}
+		
+		plan, err := m.PlanAutoMigrate(ctx, models...)
+		if err != nil {
 			return err
 		}
-	}
-	
-	return nil
+		
+		return m.applyChanges(ctx, plan.Changes)
+	})
 }
 
-// migrateModel migra uma única struct
-func (m *Migrator) migrateModel(ctx context.Context, model interface{}) error {
+// polymorphicIndexChanges planeja o índice composto (tipo, id) na tabela relacionada
+// de cada relacionamento polimórfico do modelo. Tabelas que não existem e não serão
+// criadas no plano são ignoradas.
+func (m *Migrator) polymorphicIndexChanges(ctx context.Context, model interface{}, created map[string]bool) ([]Change, error) {
 	mapping, err := m.parser.Parse(model)
 	if err != nil {
-		return err
-	}
-	
-	// Verifica se a tabela existe
-	exists, err := m.tableExists(ctx, mapping.TableName)
-	if err != nil {
-		return err
+		return nil, err
 	}
 	
-	if !exists {
-		// Cria a tabela se não existir
-		return m.createTable(ctx, *mapping)
+	var changes []Change
+	for _, rel := range mapping.Relations {
+		if rel.PolymorphicType == "" {
+			continue
+		}
+		
+		related, err := m.parser.Parse(rel.Model)
+		if err != nil {
+			return nil, err
+		}
+		
+		typeColumn, idColumn := columnName(related, rel.PolymorphicType), columnName(related, rel.ForeignKey)
+		if typeColumn == "" || idColumn == "" {
+			return nil, fmt.Errorf("colunas polimórficas de %s não encontradas em %s", rel.FieldName, related.TableName)
+		}
+		
+		name := fmt.Sprintf("idx_%s_%s_%s", related.TableName, typeColumn, idColumn)
+		if !created[related.TableName] {
+			exists, err := m.tableExists(ctx, related.TableName)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				continue
+			}
+			
+			if exists, err = m.indexExists(ctx, related.TableName, name); err != nil {
+				return nil, err
+			} else if exists {
+				continue
+			}
+		}
+		
+		changes = append(changes, Change{
+			Kind:        ChangeAddIndex,
+			Table:       related.TableName,
+			Index:       name,
+			Description: fmt.Sprintf("adicionar índice polimórfico %s em %s(%s, %s)", name, related.TableName, typeColumn, idColumn),
+			SQL:         []string{m.dialect.CreateIndexSQL(related.TableName, name, []string{typeColumn, idColumn}, false)},
+		})
 	}
 	
-	// Atualiza a tabela existente
-	return m.updateTable(ctx, *mapping)
+	return changes, nil
 }
 
-// tableExists verifica se uma tabela existe
-func (m *Migrator) tableExists(ctx context.Context, tableName string) (bool, error) {
+// indexExists verifica se um índice existe na tabela
+func (m *Migrator) indexExists(ctx context.Context, table, name string) (bool, error) {
 	var query string
 	switch m.dialect.(type) {
 	case *dialect.PostgreSQL:
 		query = `
 			SELECT EXISTS (
-				SELECT FROM pg_tables
+				SELECT 1 FROM pg_indexes
 				WHERE schemaname = 'public'
 				AND tablename = $1
+				AND indexname = $2
 			)
 		`
 	case *dialect.MySQL:
 		query = `
 			SELECT EXISTS (
-				SELECT 1 FROM information_schema.tables
+				SELECT 1 FROM information_schema.statistics
 				WHERE table_schema = DATABASE()
 				AND table_name = ?
+				AND index_name = ?
 			)
 		`
 	case *dialect.SQLite:
 		query = `
 			SELECT EXISTS (
 				SELECT 1 FROM sqlite_master
-				WHERE type = 'table'
+				WHERE type = 'index'
+				AND tbl_name = ?
 				AND name = ?
 			)
 		`
@@ -118,100 +162,66 @@ func (m *Migrator) tableExists(ctx context.Context, tableName string) (bool, err
 	}
 	
 	var exists bool
-	err := m.db.QueryRowContext(ctx, query, tableName).Scan(&exists)
+	err := m.db.QueryRowContext(ctx, query, table, name).Scan(&exists)
 	return exists, err
 }
 
-// createTable cria uma nova tabela
-func (m *Migrator) createTable(ctx context.Context, mapping TableMapping) error {
-	query := m.dialect.CreateTableSQL(mapping)
-	
-	_, err := m.db.ExecContext(ctx, query)
-	if err != nil {
-		return fmt.Errorf("erro ao criar tabela %s: %v", mapping.TableName, err)
-	}
-	
-	// Registra a migração
-	return m.recordMigration(ctx, fmt.Sprintf("create_table_%s", mapping.TableName))
-}
-
-// updateTable atualiza uma tabela existente
-func (m *Migrator) updateTable(ctx context.Context, mapping TableMapping) error {
-	// Obtém informações da tabela atual
-	currentColumns, err := m.getTableColumns(ctx, mapping.TableName)
-	if err != nil {
-		return err
-	}
-	
-	// Compara e atualiza colunas
+// columnName retorna a coluna de um campo informado pelo nome Go ou da coluna
+func columnName(mapping *TableMapping, key string) string {
 	for _, field := range mapping.Fields {
-		if _, exists := currentColumns[field.Name]; !exists {
-			// Adiciona coluna nova
-			query := m.dialect.AddColumnSQL(mapping.TableName, field)
-			if _, err := m.db.ExecContext(ctx, query); err != nil {
-				return fmt.Errorf("erro ao adicionar coluna %s: %v", field.Name, err)
-			}
-			
-			// Registra a migração
-			if err := m.recordMigration(ctx, fmt.Sprintf("add_column_%s_%s", 
-				mapping.TableName, field.Name)); err != nil {
-				return err
-			}
+		if field.FieldName == key || field.Name == key {
+			return field.Name
 		}
 	}
-	
-	return nil
+	return ""
 }
 
-// getTableColumns obtém as colunas existentes de uma tabela
-func (m *Migrator) getTableColumns(ctx context.Context, tableName string) (map[string]bool, error) {
+// tableExists verifica se uma tabela existe
+func (m *Migrator) tableExists(ctx context.Context, tableName string) (bool, error) {
 	var query string
 	switch m.dialect.(type) {
 	case *dialect.PostgreSQL:
 		query = `
-			SELECT column_name
-			FROM information_schema.columns
-			WHERE table_name = $1
+			SELECT EXISTS (
+				SELECT FROM pg_tables
+				WHERE schemaname = 'public'
+				AND tablename = $1
+			)
 		`
 	case *dialect.MySQL:
 		query = `
-			SELECT column_name
-			FROM information_schema.columns
-			WHERE table_name = ?
-			AND table_schema = DATABASE()
+			SELECT EXISTS (
+				SELECT 1 FROM information_schema.tables
+				WHERE table_schema = DATABASE()
+				AND table_name = ?
+			)
 		`
 	case *dialect.SQLite:
-		query = fmt.Sprintf("PRAGMA table_info(%s)", m.dialect.Quote(tableName))
+		query = `
+			SELECT EXISTS (
+				SELECT 1 FROM sqlite_master
+				WHERE type = 'table'
+				AND name = ?
+			)
+		`
 	default:
-		return nil, fmt.Errorf("dialeto não suportado")
-	}
-	
-	rows, err := m.db.QueryContext(ctx, query, tableName)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	
-	columns := make(map[string]bool)
-	for rows.Next() {
-		var columnName string
-		if err := rows.Scan(&columnName); err != nil {
-			return nil, err
-		}
-		columns[columnName] = true
+		return false, fmt.Errorf("dialeto não suportado")
 	}
 	
-	return columns, rows.Err()
+	var exists bool
+	err := m.db.QueryRowContext(ctx, query, tableName).Scan(&exists)
+	return exists, err
 }
 
 // recordMigration registra uma migração aplicada
-func (m *Migrator) recordMigration(ctx context.Context, name string) error {
+func (m *Migrator) recordMigration(ctx context.Context, conn Conn, name string) error {
 	query := fmt.Sprintf(`
 		INSERT INTO %s (name, timestamp, applied)
-		VALUES (?, ?, ?)
-	`, m.dialect.Quote("migrations"))
+		VALUES (%s, %s, %s)
+	`, m.dialect.Quote("migrations"),
+		m.dialect.Placeholder(1), m.dialect.Placeholder(2), m.dialect.Placeholder(3))
 	
-	_, err := m.db.ExecContext(ctx, query, name, time.Now(), true)
+	_, err := conn.ExecContext(ctx, query, name, time.Now(), true)
 	return err
 }
 
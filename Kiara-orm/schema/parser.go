@@ -1,12 +1,28 @@
 package schema
 
 import (
+	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 	
 	"github.com/Flavio-coutinho/Kiara-orm/types"
 )
 
+// TableMapping é o mapeamento de tabela usado pelos pacotes que dependem do schema
+type TableMapping = types.TableMapping
+
+// FieldMapping é o mapeamento de campo usado pelos pacotes que dependem do schema
+type FieldMapping = types.FieldMapping
+
+// Nomes de coluna reconhecidos por convenção como timestamps automáticos
+const (
+	CreatedAtColumn = "created_at"
+	UpdatedAtColumn = "updated_at"
+)
+
 // Parser é responsável por analisar as estruturas Go e extrair informações de mapeamento
 type Parser struct {
 	typeMapper *types.TypeMapper
@@ -19,6 +35,16 @@ func NewParser() *Parser {
 	}
 }
 
+// Prioridade das colunas de índices compostos sem priority na tag
+const defaultIndexPriority = 10
+
+// indexColumn é uma coluna de índice declarada na tag, antes do agrupamento por nome
+type indexColumn struct {
+	index    types.IndexMapping
+	column   string
+	priority int
+}
+
 // Parse analisa uma estrutura Go e retorna seu mapeamento
 func (p *Parser) Parse(model interface{}) (*types.TableMapping, error) {
 	t := reflect.TypeOf(model)
@@ -33,6 +59,35 @@ func (p *Parser) Parse(model interface{}) (*types.TableMapping, error) {
 		return nil, fmt.Errorf("modelo deve ser uma struct, recebido: %v", t.Kind())
 	}
 	
+	mapping, err := p.parseStruct(t, t)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Relacionamentos de structs embutidas podem usar colunas da struct externa
+	if err := p.relationForeignKeys(t, mapping); err != nil {
+		return nil, err
+	}
+	
+	// Nomes padrão usam a tabela final, inclusive para campos de structs embutidas
+	for i, fk := range mapping.ForeignKeys {
+		if fk.Name == "" {
+			mapping.ForeignKeys[i].Name = fmt.Sprintf("fk_%s_%s", mapping.TableName, fk.Column)
+		}
+	}
+	for i, index := range mapping.Indexes {
+		if index.Name == "" {
+			mapping.Indexes[i].Name = fmt.Sprintf("idx_%s_%s", mapping.TableName, strings.Join(index.Columns, "_"))
+		}
+	}
+	
+	return mapping, nil
+}
+
+// parseStruct lê campos, relacionamentos, chaves estrangeiras e índices da struct t.
+// owner é o modelo analisado, dono dos relacionamentos declarados em structs embutidas.
+func (p *Parser) parseStruct(owner, t reflect.Type) (*types.TableMapping, error) {
+	var indexColumns []indexColumn
 	mapping := &types.TableMapping{
 		TableName: p.getTableName(t),
 		Fields:    make([]types.FieldMapping, 0),
@@ -42,17 +97,46 @@ func (p *Parser) Parse(model interface{}) (*types.TableMapping, error) {
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
 		
-		// Ignora campos não exportados
-		if !field.IsExported() {
+		// Ignora campos não exportados e o campo TableName, que só define o nome da tabela
+		if !field.IsExported() || field.Name == "TableName" {
+			continue
+		}
+		
+		// Structs embutidas sem tag (ex.: softdelete.SoftDelete) contribuem com seus campos
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("db") == "" {
+			embedded, err := p.parseStruct(owner, field.Type)
+			if err != nil {
+				return nil, err
+			}
+			mapping.Fields = append(mapping.Fields, embedded.Fields...)
+			mapping.Relations = append(mapping.Relations, embedded.Relations...)
+			mapping.ForeignKeys = append(mapping.ForeignKeys, embedded.ForeignKeys...)
+			mapping.Indexes = append(mapping.Indexes, embedded.Indexes...)
+			continue
+		}
+		
+		// Campos com a tag rel são relacionamentos, não colunas
+		if tag := field.Tag.Get("rel"); tag != "" {
+			relationMapping, err := p.parseRelation(owner, field, tag)
+			if err != nil {
+				return nil, err
+			}
+			mapping.Relations = append(mapping.Relations, *relationMapping)
 			continue
 		}
 		
 		fieldMapping := p.parseField(field)
 		if fieldMapping != nil {
 			mapping.Fields = append(mapping.Fields, *fieldMapping)
+			
+			if fk := p.parseForeignKey(fieldMapping.Name, field.Tag.Get("db")); fk != nil {
+				mapping.ForeignKeys = append(mapping.ForeignKeys, *fk)
+			}
+			indexColumns = append(indexColumns, p.parseIndexes(fieldMapping.Name, field.Tag.Get("db"))...)
 		}
 	}
 	
+	mapping.Indexes = append(mapping.Indexes, mergeIndexes(indexColumns)...)
 	return mapping, nil
 }
 
@@ -64,19 +148,60 @@ func (p *Parser) parseField(field reflect.StructField) *types.FieldMapping {
 	}
 	
 	mapping := &types.FieldMapping{
-		Name: p.getFieldName(field, tag),
-		Type: p.typeMapper.GetDataType(field.Type.String()),
+		Name:      p.getFieldName(field, tag),
+		FieldName: field.Name,
+		Type:      p.typeMapper.GetDataType(field.Type.String()),
+	}
+	
+	// Ponteiros (ex.: DeletedAt *time.Time) aceitam NULL
+	if field.Type.Kind() == reflect.Ptr {
+		mapping.Type = p.typeMapper.GetDataType(field.Type.Elem().String())
+		mapping.IsNullable = true
 	}
 	
 	// Processa as opções da tag
-	p.parseTagOptions(mapping, tag)
+	p.parseTagOptions(mapping, field.Type, tag)
+	
+	// Reconhece created_at/updated_at por convenção quando a tag não declara nada
+	if mapping.AutoCreateTime == types.AutoTimeNone && mapping.AutoUpdateTime == types.AutoTimeNone {
+		switch mapping.Name {
+		case CreatedAtColumn:
+			mapping.AutoCreateTime = p.autoTimeFor(field.Type, "")
+		case UpdatedAtColumn:
+			mapping.AutoUpdateTime = p.autoTimeFor(field.Type, "")
+		}
+	}
 	
 	return mapping
 }
 
-// parseTagOptions processa as opções da tag db
-func (p *Parser) parseTagOptions(mapping *types.FieldMapping, tag string) {
-	parts := strings.Split(tag, ",")
+// splitTag separa as opções da tag db nas vírgulas que não estão entre parênteses
+// ou aspas simples, preservando expressões SQL como IN ('a', 'b')
+func splitTag(tag string) []string {
+	var parts []string
+	depth, quoted, start := 0, false, 0
+	
+	for i, r := range tag {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case quoted:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, tag[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, tag[start:])
+}
+
+// parseTagOptions processa as opções da tag db. default, check e comment recebem
+// expressões SQL: db:"status,default:'ativo',check:status IN ('ativo', 'inativo')"
+func (p *Parser) parseTagOptions(mapping *types.FieldMapping, fieldType reflect.Type, tag string) {
+	parts := splitTag(tag)
 	
 	for i, part := range parts {
 		if i == 0 && part != "" {
@@ -93,11 +218,315 @@ func (p *Parser) parseTagOptions(mapping *types.FieldMapping, tag string) {
 			mapping.IsUnique = true
 		case part == "nullable":
 			mapping.IsNullable = true
+		case part == "version":
+			mapping.IsVersion = true
 		case strings.HasPrefix(part, "size:"):
 			size, _ := strconv.Atoi(strings.TrimPrefix(part, "size:"))
 			mapping.Size = size
+		case strings.HasPrefix(part, "default:"):
+			mapping.Default = strings.TrimPrefix(part, "default:")
+		case strings.HasPrefix(part, "check:"):
+			mapping.Check = strings.TrimPrefix(part, "check:")
+		case strings.HasPrefix(part, "comment:"):
+			mapping.Comment = strings.TrimPrefix(part, "comment:")
+		case part == "autoCreateTime" || strings.HasPrefix(part, "autoCreateTime:"):
+			mapping.AutoCreateTime = p.autoTimeFor(fieldType, strings.TrimPrefix(strings.TrimPrefix(part, "autoCreateTime"), ":"))
+		case part == "autoUpdateTime" || strings.HasPrefix(part, "autoUpdateTime:"):
+			mapping.AutoUpdateTime = p.autoTimeFor(fieldType, strings.TrimPrefix(strings.TrimPrefix(part, "autoUpdateTime"), ":"))
+		}
+	}
+}
+
+// parseForeignKey lê a chave estrangeira da tag db, por exemplo:
+//
+//	db:"user_id,fk:users.id,onDelete:cascade"
+//	db:"author_id,fk:authors,onDelete:set null,onUpdate:cascade"
+//
+// Sem a coluna referenciada, usa id.
+func (p *Parser) parseForeignKey(column, tag string) *types.ForeignKeyMapping {
+	var fk *types.ForeignKeyMapping
+	var onDelete, onUpdate string
+	
+	for _, part := range splitTag(tag)[1:] {
+		switch {
+		case strings.HasPrefix(part, "fk:"):
+			table, reference := strings.TrimPrefix(part, "fk:"), "id"
+			if dot := strings.Index(table, "."); dot >= 0 {
+				table, reference = table[:dot], table[dot+1:]
+			}
+			fk = &types.ForeignKeyMapping{Column: column, ReferenceTable: table, ReferenceColumn: reference}
+		case strings.HasPrefix(part, "onDelete:"):
+			onDelete = referentialAction(strings.TrimPrefix(part, "onDelete:"))
+		case strings.HasPrefix(part, "onUpdate:"):
+			onUpdate = referentialAction(strings.TrimPrefix(part, "onUpdate:"))
+		}
+	}
+	
+	if fk != nil {
+		fk.OnDelete, fk.OnUpdate = onDelete, onUpdate
+	}
+	return fk
+}
+
+// parseIndexes lê os índices declarados na tag db. As opções priority, where e
+// method valem para o index ou uniqueIndex que as precede, por exemplo:
+//
+//	db:"email,uniqueIndex"
+//	db:"tenant_id,index:idx_tenant_email,priority:1"
+//	db:"email,index:idx_tenant_email,priority:2,where:deleted_at IS NULL"
+//	db:"tags,index,method:gin"
+func (p *Parser) parseIndexes(column, tag string) []indexColumn {
+	var columns []indexColumn
+	current := -1
+	
+	for _, part := range splitTag(tag)[1:] {
+		name, value, _ := strings.Cut(part, ":")
+		
+		switch name {
+		case "index", "uniqueIndex":
+			columns = append(columns, indexColumn{
+				index:    types.IndexMapping{Name: value, Unique: name == "uniqueIndex"},
+				column:   column,
+				priority: defaultIndexPriority,
+			})
+			current = len(columns) - 1
+		case "priority":
+			if current >= 0 {
+				columns[current].priority, _ = strconv.Atoi(value)
+			}
+		case "where":
+			if current >= 0 {
+				columns[current].index.Where = value
+			}
+		case "method":
+			if current >= 0 {
+				columns[current].index.Method = strings.ToUpper(value)
+			}
+		}
+	}
+	return columns
+}
+
+// mergeIndexes agrupa as colunas declaradas com o mesmo nome de índice, ordenadas
+// pela prioridade e, no empate, pela ordem dos campos. Índices sem nome são de uma coluna.
+func mergeIndexes(columns []indexColumn) []types.IndexMapping {
+	var indexes []types.IndexMapping
+	var members [][]indexColumn
+	position := make(map[string]int)
+	
+	for _, column := range columns {
+		key := column.index.Name
+		if key == "" {
+			key = "\x00" + column.column
+		}
+		
+		i, ok := position[key]
+		if !ok {
+			i = len(indexes)
+			position[key] = i
+			indexes = append(indexes, column.index)
+			members = append(members, nil)
+		}
+		
+		index := &indexes[i]
+		index.Unique = index.Unique || column.index.Unique
+		if index.Where == "" {
+			index.Where = column.index.Where
+		}
+		if index.Method == "" {
+			index.Method = column.index.Method
+		}
+		members[i] = append(members[i], column)
+	}
+	
+	for i := range indexes {
+		sort.SliceStable(members[i], func(a, b int) bool { return members[i][a].priority < members[i][b].priority })
+		for _, member := range members[i] {
+			indexes[i].Columns = append(indexes[i].Columns, member.column)
+		}
+	}
+	return indexes
+}
+
+// relationForeignKeys cria as chaves estrangeiras dos belongs_to declarados com
+// constraint, onDelete ou onUpdate. Colunas que já têm a tag fk prevalecem.
+func (p *Parser) relationForeignKeys(owner reflect.Type, mapping *types.TableMapping) error {
+	for _, rel := range mapping.Relations {
+		if rel.Kind != types.RelBelongsTo || !rel.Constraint {
+			continue
+		}
+		
+		column := columnName(mapping, rel.ForeignKey)
+		if column == "" {
+			return fmt.Errorf("chave estrangeira %s de %s não encontrada em %s", rel.ForeignKey, rel.FieldName, owner.Name())
+		}
+		
+		declared := false
+		for _, fk := range mapping.ForeignKeys {
+			declared = declared || fk.Column == column
+		}
+		if declared {
+			continue
+		}
+		
+		related := reflect.TypeOf(rel.Model).Elem()
+		reference := p.referenceColumn(related, rel.ReferenceKey)
+		if reference == "" {
+			return fmt.Errorf("chave referenciada por %s não encontrada em %s", rel.FieldName, related.Name())
+		}
+		
+		mapping.ForeignKeys = append(mapping.ForeignKeys, types.ForeignKeyMapping{
+			Column:          column,
+			ReferenceTable:  p.getTableName(related),
+			ReferenceColumn: reference,
+			OnDelete:        rel.OnDelete,
+			OnUpdate:        rel.OnUpdate,
+		})
+	}
+	return nil
+}
+
+// referenceColumn retorna a coluna do campo key do modelo relacionado, ou a
+// chave primária quando key é vazio. Lê só os campos, sem seguir relacionamentos,
+// incluindo os de structs embutidas, como em parseStruct.
+func (p *Parser) referenceColumn(related reflect.Type, key string) string {
+	for i := 0; i < related.NumField(); i++ {
+		field := related.Field(i)
+		if !field.IsExported() || field.Name == "TableName" || field.Tag.Get("rel") != "" {
+			continue
+		}
+		
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("db") == "" {
+			if column := p.referenceColumn(field.Type, key); column != "" {
+				return column
+			}
+			continue
+		}
+		
+		fieldMapping := p.parseField(field)
+		if fieldMapping == nil {
+			continue
+		}
+		
+		if key == "" {
+			if fieldMapping.IsPrimaryKey {
+				return fieldMapping.Name
+			}
+		} else if fieldMapping.FieldName == key || fieldMapping.Name == key {
+			return fieldMapping.Name
+		}
+	}
+	return ""
+}
+
+// referentialAction normaliza a ação da chave estrangeira: "set null" → SET NULL
+func referentialAction(action string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(action), " "))
+}
+
+// autoTimeFor determina o modo de timestamp automático pelo tipo Go do campo.
+// Campos inteiros guardam segundos Unix, ou milissegundos com a unidade "milli".
+func (p *Parser) autoTimeFor(fieldType reflect.Type, unit string) types.AutoTime {
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		if unit == "milli" {
+			return types.AutoTimeUnixMilli
 		}
+		return types.AutoTimeUnix
+	}
+	
+	if fieldType == reflect.TypeOf(time.Time{}) {
+		return types.AutoTimeDateTime
+	}
+	
+	return types.AutoTimeNone
+}
+
+// parseRelation analisa a tag rel de um campo, por exemplo:
+//
+//	rel:"belongs_to,fk:user_id"
+//	rel:"has_many,fk:post_id,preload"
+//	rel:"many2many,join:post_tags,joinFk:post_id,joinRef:tag_id"
+//	rel:"has_many,polymorphic:Commentable,polymorphicValue:post"
+//	rel:"belongs_to,fk:user_id,onDelete:cascade"
+func (p *Parser) parseRelation(owner reflect.Type, field reflect.StructField, tag string) (*types.RelationMapping, error) {
+	related := field.Type
+	for related.Kind() == reflect.Ptr || related.Kind() == reflect.Slice {
+		related = related.Elem()
+	}
+	
+	if related.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("relacionamento %s deve apontar para uma struct", field.Name)
 	}
+	
+	parts := strings.Split(tag, ",")
+	mapping := &types.RelationMapping{
+		FieldName: field.Name,
+		Kind:      types.RelationKind(parts[0]),
+		Model:     reflect.New(related).Interface(),
+	}
+	
+	for _, part := range parts[1:] {
+		switch {
+		case part == "preload":
+			mapping.Preload = true
+		case strings.HasPrefix(part, "fk:"):
+			mapping.ForeignKey = strings.TrimPrefix(part, "fk:")
+		case strings.HasPrefix(part, "ref:"):
+			mapping.ReferenceKey = strings.TrimPrefix(part, "ref:")
+		case strings.HasPrefix(part, "join:"):
+			mapping.JoinTable = strings.TrimPrefix(part, "join:")
+		case strings.HasPrefix(part, "joinFk:"):
+			mapping.JoinForeignKey = strings.TrimPrefix(part, "joinFk:")
+		case strings.HasPrefix(part, "joinRef:"):
+			mapping.JoinReferenceKey = strings.TrimPrefix(part, "joinRef:")
+		case strings.HasPrefix(part, "assoc:"):
+			mapping.AssociationKey = strings.TrimPrefix(part, "assoc:")
+		case strings.HasPrefix(part, "polymorphic:"):
+			// polymorphic:Commentable usa os campos CommentableID e CommentableType
+			name := strings.TrimPrefix(part, "polymorphic:")
+			mapping.ForeignKey = name + "ID"
+			mapping.PolymorphicType = name + "Type"
+		case strings.HasPrefix(part, "polymorphicValue:"):
+			mapping.PolymorphicValue = strings.TrimPrefix(part, "polymorphicValue:")
+		case part == "constraint":
+			mapping.Constraint = true
+		case strings.HasPrefix(part, "onDelete:"):
+			mapping.Constraint = true
+			mapping.OnDelete = referentialAction(strings.TrimPrefix(part, "onDelete:"))
+		case strings.HasPrefix(part, "onUpdate:"):
+			mapping.Constraint = true
+			mapping.OnUpdate = referentialAction(strings.TrimPrefix(part, "onUpdate:"))
+		}
+	}
+	
+	if mapping.PolymorphicType != "" && mapping.Kind != types.RelHasOne && mapping.Kind != types.RelHasMany {
+		return nil, fmt.Errorf("relacionamento polimórfico %s deve ser has_one ou has_many", field.Name)
+	}
+	
+	// Chaves estrangeiras padrão: UserID em belongs_to "User", <Modelo>ID em has_one/has_many
+	switch mapping.Kind {
+	case types.RelBelongsTo:
+		if mapping.ForeignKey == "" {
+			mapping.ForeignKey = field.Name + "ID"
+		}
+	case types.RelHasOne, types.RelHasMany:
+		if mapping.ForeignKey == "" {
+			mapping.ForeignKey = owner.Name() + "ID"
+		}
+	case types.RelManyToMany:
+		if mapping.JoinTable == "" {
+			mapping.JoinTable = p.getTableName(owner) + "_" + p.getTableName(related)
+		}
+	default:
+		return nil, fmt.Errorf("tipo de relacionamento desconhecido em %s: %s", field.Name, parts[0])
+	}
+	
+	return mapping, nil
 }
 
 // getTableName retorna o nome da tabela para a struct
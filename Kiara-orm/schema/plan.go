@@ -0,0 +1,225 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ChangeKind das migrações versionadas no plano
+const (
+	ChangeMigrateUp   ChangeKind = "migrate_up"
+	ChangeMigrateDown ChangeKind = "migrate_down"
+)
+
+// Plan é a lista ordenada de alterações que uma migração executaria
+type Plan struct {
+	Changes []Change
+}
+
+// Statements retorna, em ordem, os comandos SQL que seriam executados
+func (p *Plan) Statements() []string {
+	var statements []string
+	for _, change := range p.Changes {
+		if !change.Skipped {
+			statements = append(statements, change.SQL...)
+		}
+	}
+	return statements
+}
+
+// HasDestructive indica se o plano contém alterações destrutivas
+func (p *Plan) HasDestructive() bool {
+	for _, change := range p.Changes {
+		if change.Destructive {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteSQL escreve o plano como um script SQL comentado. Alterações ignoradas
+// aparecem com os comandos comentados.
+func (p *Plan) WriteSQL(w io.Writer) error {
+	var builder strings.Builder
+	
+	builder.WriteString(fmt.Sprintf("-- Plano de migração: %d alteração(ões)\n", len(p.Changes)))
+	
+	for i, change := range p.Changes {
+		builder.WriteString(fmt.Sprintf("\n-- [%d] %s\n", i+1, change.Description))
+		if change.Destructive {
+			builder.WriteString("-- DESTRUTIVA: pode perder dados\n")
+		}
+		
+		prefix := ""
+		if change.Skipped {
+			builder.WriteString("-- IGNORADA: " + change.SkipReason + "\n")
+			prefix = "-- "
+		}
+		
+		for _, statement := range change.SQL {
+			statement = strings.TrimSpace(statement)
+			if !strings.HasSuffix(statement, ";") {
+				statement += ";"
+			}
+			builder.WriteString(prefix + strings.ReplaceAll(statement, "\n", "\n"+prefix) + "\n")
+		}
+	}
+	
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// WriteFile grava o plano em um arquivo .sql
+func (p *Plan) WriteFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("erro ao criar arquivo %s: %v", path, err)
+	}
+	defer file.Close()
+	
+	if err := p.WriteSQL(file); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// PlanAutoMigrate calcula, sem executar, as alterações que o AutoMigrate aplicaria
+func (m *Migrator) PlanAutoMigrate(ctx context.Context, models ...interface{}) (*Plan, error) {
+	plan := &Plan{}
+	created := make(map[string]bool)
+	
+	mappings := make([]*TableMapping, len(models))
+	for i, model := range models {
+		mapping, err := m.parser.Parse(model)
+		if err != nil {
+			return nil, err
+		}
+		mappings[i] = mapping
+	}
+	mappings = sortByDependency(mappings)
+	
+	var deferred []Change
+	for i, mapping := range mappings {
+		ready, later, err := m.deferForeignKeys(ctx, *mapping, mappings[i+1:])
+		if err != nil {
+			return nil, err
+		}
+		
+		changes, err := m.diffMapping(ctx, ready)
+		if err != nil {
+			return nil, err
+		}
+		
+		for _, change := range changes {
+			if change.Kind == ChangeCreateTable {
+				created[change.Table] = true
+			}
+		}
+		plan.add(m, changes...)
+		
+		for _, fk := range later {
+			deferred = append(deferred, m.addForeignKey(mapping.TableName, fk))
+		}
+	}
+	plan.add(m, deferred...)
+	
+	// Índices polimórficos dependem da tabela relacionada, por isso vêm depois.
+	// Vários modelos podem apontar para a mesma tabela.
+	planned := make(map[string]bool)
+	for _, model := range models {
+		changes, err := m.polymorphicIndexChanges(ctx, model, created)
+		if err != nil {
+			return nil, err
+		}
+		
+		for _, change := range changes {
+			if !planned[change.Name()] {
+				planned[change.Name()] = true
+				plan.add(m, change)
+			}
+		}
+	}
+	
+	return plan, nil
+}
+
+// PlanMigrateUp lista, sem executar, as migrações versionadas pendentes.
+// Migrações escritas em Go aparecem sem SQL.
+func (m *Migrator) PlanMigrateUp(ctx context.Context) (*Plan, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	plan := &Plan{}
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			plan.Changes = append(plan.Changes, migrationChange(migration, true))
+		}
+	}
+	return plan, nil
+}
+
+// PlanRollback lista, sem executar, as migrações que Rollback(n) desfaria
+func (m *Migrator) PlanRollback(ctx context.Context, n int) (*Plan, error) {
+	versions, err := m.lastApplied(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+	
+	plan := &Plan{}
+	for _, version := range versions {
+		migration, err := m.reversible(version)
+		if err != nil {
+			return nil, err
+		}
+		plan.Changes = append(plan.Changes, migrationChange(migration, false))
+	}
+	return plan, nil
+}
+
+// add acrescenta alterações ao plano marcando as que não seriam aplicadas
+func (p *Plan) add(m *Migrator, changes ...Change) {
+	for _, change := range changes {
+		if reason := m.skipReason(change); reason != "" {
+			change.Skipped, change.SkipReason = true, reason
+		}
+		p.Changes = append(p.Changes, change)
+	}
+}
+
+// migrationChange descreve uma migração versionada no plano
+func migrationChange(migration *VersionedMigration, up bool) Change {
+	change := Change{
+		Kind:        ChangeMigrateUp,
+		Description: fmt.Sprintf("aplicar migração %d (%s)", migration.Version, migration.Name),
+		SQL:         migration.UpSQL,
+	}
+	if !up {
+		change.Kind = ChangeMigrateDown
+		change.Description = fmt.Sprintf("desfazer migração %d (%s)", migration.Version, migration.Name)
+		change.SQL = migration.DownSQL
+	}
+	
+	if change.SQL == nil {
+		change.Description += " — função Go, SQL não disponível"
+	}
+	change.Destructive = destructiveSQL(change.SQL)
+	return change
+}
+
+// destructiveSQL reconhece comandos que removem dados ou estruturas
+func destructiveSQL(statements []string) bool {
+	for _, statement := range statements {
+		upper := strings.ToUpper(statement)
+		for _, keyword := range []string{"DROP ", "TRUNCATE ", "DELETE "} {
+			if strings.Contains(upper, keyword) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Marcadores das seções de um arquivo .sql de migração
+const (
+	sqlUpMarker   = "-- +up"
+	sqlDownMarker = "-- +down"
+)
+
+// RegisterSQL registra uma migração escrita em SQL. Cada seção pode conter
+// vários comandos; um comando termina na linha que acaba em ponto e vírgula.
+func (m *Migrator) RegisterSQL(version int64, name, upSQL, downSQL string) error {
+	var down MigrationFunc
+	if strings.TrimSpace(downSQL) != "" {
+		down = sqlMigration(downSQL)
+	}
+	if err := m.Register(version, name, sqlMigration(upSQL), down); err != nil {
+		return err
+	}
+	
+	migration := m.migration(version)
+	migration.UpSQL = splitStatements(upSQL)
+	migration.DownSQL = splitStatements(downSQL)
+	migration.Checksum = checksum(migration.UpSQL, migration.DownSQL)
+	return nil
+}
+
+// LoadSQLDir registra os arquivos .sql do diretório, nomeados como
+// <versão>_<nome>.sql (ex.: 20240101120000_create_users.sql), com as seções
+// -- +up e -- +down
+func (m *Migrator) LoadSQLDir(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("erro ao ler diretório de migrações %s: %v", dir, err)
+	}
+	
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		
+		version, name, err := parseMigrationFileName(entry.Name())
+		if err != nil {
+			return err
+		}
+		
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("erro ao ler migração %s: %v", entry.Name(), err)
+		}
+		
+		up, down, err := ParseSQLMigration(string(content))
+		if err != nil {
+			return fmt.Errorf("migração %s: %v", entry.Name(), err)
+		}
+		
+		if err := m.RegisterSQL(version, name, up, down); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseSQLMigration separa o conteúdo de um arquivo de migração nas seções up e down
+func ParseSQLMigration(content string) (up, down string, err error) {
+	var upLines, downLines []string
+	var current *[]string
+	
+	for _, line := range strings.Split(content, "\n") {
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case sqlUpMarker:
+			current = &upLines
+			continue
+		case sqlDownMarker:
+			current = &downLines
+			continue
+		}
+		
+		if current != nil {
+			*current = append(*current, line)
+		} else if strings.TrimSpace(line) != "" && !strings.HasPrefix(strings.TrimSpace(line), "--") {
+			return "", "", fmt.Errorf("comando fora das seções %s/%s", sqlUpMarker, sqlDownMarker)
+		}
+	}
+	
+	up = strings.TrimSpace(strings.Join(upLines, "\n"))
+	if up == "" {
+		return "", "", fmt.Errorf("seção %s vazia", sqlUpMarker)
+	}
+	return up, strings.TrimSpace(strings.Join(downLines, "\n")), nil
+}
+
+// parseMigrationFileName extrai versão e nome de <versão>_<nome>.sql
+func parseMigrationFileName(fileName string) (int64, string, error) {
+	base := strings.TrimSuffix(fileName, path.Ext(fileName))
+	prefix, name, _ := strings.Cut(base, "_")
+	
+	version, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("nome de migração inválido %s: esperado <versão>_<nome>.sql", fileName)
+	}
+	return version, name, nil
+}
+
+// sqlMigration executa os comandos SQL um a um
+func sqlMigration(script string) MigrationFunc {
+	statements := splitStatements(script)
+	return func(ctx context.Context, conn Conn) error {
+		for _, statement := range statements {
+			if _, err := conn.ExecContext(ctx, statement); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// splitStatements divide o script nos comandos terminados em ponto e vírgula no fim da linha
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if current.Len() == 0 && (trimmed == "" || strings.HasPrefix(trimmed, "--")) {
+			continue
+		}
+		
+		current.WriteString(line)
+		current.WriteString("\n")
+		
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+	}
+	
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
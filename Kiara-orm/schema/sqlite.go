@@ -0,0 +1,258 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// sqliteObject é um índice ou trigger criado explicitamente em uma tabela do SQLite
+type sqliteObject struct {
+	Type string
+	Name string
+	SQL  string
+}
+
+// planSQLiteRebuild substitui as alterações sem ALTER nativo no SQLite (tipo, nulidade,
+// constraints e DROP COLUMN em versões antigas ou colunas indexadas) por uma única
+// recriação da tabela, seguindo o procedimento de 12 passos da documentação do SQLite
+func (m *Migrator) planSQLiteRebuild(ctx context.Context, mapping TableMapping, info *TableInfo, changes []Change) ([]Change, error) {
+	native, err := m.sqliteSupportsDropColumn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	objects, err := m.sqliteObjects(ctx, mapping.TableName)
+	if err != nil {
+		return nil, err
+	}
+	
+	hasTriggers := false
+	for _, object := range objects {
+		hasTriggers = hasTriggers || object.Type == "trigger"
+	}
+	
+	// Índices novos vêm depois da recriação, que só recria os índices já existentes
+	var kept, covered, indexes []Change
+	for _, change := range changes {
+		switch {
+		case change.Kind == ChangeDropColumn && native && canDropNatively(info, change.Column, hasTriggers):
+			kept = append(kept, change)
+		case change.Kind == ChangeDropColumn, len(change.SQL) == 0:
+			covered = append(covered, change)
+		case change.Kind == ChangeAddIndex:
+			indexes = append(indexes, change)
+		default:
+			kept = append(kept, change)
+		}
+	}
+	
+	if len(covered) == 0 {
+		return append(kept, indexes...), nil
+	}
+	
+	foreignKeys, err := m.sqliteForeignKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Índices removidos por outras alterações não são recriados
+	skip := make(map[string]bool)
+	for _, change := range changes {
+		if change.Kind == ChangeDropIndex {
+			skip[change.Index] = true
+		}
+	}
+	
+	rebuild := m.sqliteRebuild(mapping, info, objects, skip, foreignKeys)
+	descriptions := make([]string, len(covered))
+	for i, change := range covered {
+		descriptions[i] = change.Description
+		rebuild.Destructive = rebuild.Destructive || change.Destructive
+	}
+	rebuild.Description = fmt.Sprintf("recriar tabela %s (%s)", mapping.TableName, strings.Join(descriptions, "; "))
+	
+	return append(append(kept, rebuild), indexes...), nil
+}
+
+// sqliteRebuild gera os comandos que recriam a tabela conforme o mapeamento:
+// cria a nova tabela, copia os dados, remove a antiga, renomeia e recria índices e triggers
+func (m *Migrator) sqliteRebuild(mapping TableMapping, info *TableInfo, objects []sqliteObject, skip map[string]bool, foreignKeys bool) Change {
+	table := mapping.TableName
+	temp := "_new_" + table
+	
+	// Colunas novas já foram adicionadas pelas alterações anteriores
+	columns := make([]string, len(mapping.Fields))
+	for i, field := range mapping.Fields {
+		columns[i] = m.dialect.Quote(field.Name)
+	}
+	columnList := strings.Join(columns, ", ")
+	
+	var statements, cleanup []string
+	if foreignKeys {
+		statements = append(statements, "PRAGMA foreign_keys = OFF")
+	}
+	
+	statements = append(statements,
+		"BEGIN",
+		"DROP TABLE IF EXISTS "+m.dialect.Quote(temp),
+		// Só o nome da tabela muda: constraints nomeadas mantêm o nome definitivo
+		strings.Replace(m.dialect.CreateTableSQL(mapping), m.dialect.Quote(table), m.dialect.Quote(temp), 1),
+		fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", m.dialect.Quote(temp), columnList, columnList, m.dialect.Quote(table)),
+		"DROP TABLE "+m.dialect.Quote(table),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", m.dialect.Quote(temp), m.dialect.Quote(table)),
+	)
+	
+	for _, object := range objects {
+		if skip[object.Name] || !indexStillValid(mapping, info, object) {
+			continue
+		}
+		statements = append(statements, object.SQL)
+	}
+	
+	// Passo 10: com as chaves desligadas, a cópia pode ter violado alguma delas
+	if foreignKeys {
+		statements = append(statements, fmt.Sprintf("%s(%s)", foreignKeyCheck, m.dialect.Quote(table)))
+	}
+	
+	statements = append(statements, "COMMIT")
+	cleanup = append(cleanup, "ROLLBACK")
+	
+	if foreignKeys {
+		statements = append(statements, "PRAGMA foreign_keys = ON")
+		cleanup = append(cleanup, "PRAGMA foreign_keys = ON")
+	}
+	
+	return Change{
+		Kind:    ChangeRebuild,
+		Table:   table,
+		SQL:     statements,
+		cleanup: cleanup,
+	}
+}
+
+// foreignKeyCheck lista as violações de chaves estrangeiras de uma tabela do SQLite
+const foreignKeyCheck = "PRAGMA foreign_key_check"
+
+// execStatement executa um comando da alteração. O PRAGMA foreign_key_check retorna
+// as violações como linhas: qualquer linha faz a alteração falhar.
+func execStatement(ctx context.Context, conn *sql.Conn, statement string) error {
+	if !strings.HasPrefix(statement, foreignKeyCheck) {
+		_, err := conn.ExecContext(ctx, statement)
+		return err
+	}
+	
+	rows, err := conn.QueryContext(ctx, statement)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	
+	if rows.Next() {
+		var table, parent string
+		var rowid sql.NullInt64
+		var fkid int
+		if err := rows.Scan(&table, &rowid, &parent, &fkid); err != nil {
+			return err
+		}
+		return fmt.Errorf("a linha %d de %s viola a chave estrangeira para %s", rowid.Int64, table, parent)
+	}
+	return rows.Err()
+}
+
+// indexStillValid indica se um índice deve ser recriado: todas as suas colunas continuam
+// no mapeamento e ele não duplica o UNIQUE declarado na própria coluna
+func indexStillValid(mapping TableMapping, info *TableInfo, object sqliteObject) bool {
+	if object.Type != "index" {
+		return true
+	}
+	
+	index, ok := info.Index(object.Name)
+	if !ok {
+		return true
+	}
+	
+	for _, column := range index.Columns {
+		if columnName(&mapping, column) == "" {
+			return false
+		}
+	}
+	
+	if index.Unique && len(index.Columns) == 1 {
+		for _, field := range mapping.Fields {
+			if field.Name == index.Columns[0] && field.IsUnique {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// canDropNatively verifica as restrições do DROP COLUMN nativo: a coluna não pode
+// ser chave primária nem fazer parte de índices, e a tabela não pode ter triggers
+func canDropNatively(info *TableInfo, column string, hasTriggers bool) bool {
+	if hasTriggers {
+		return false
+	}
+	
+	if col, ok := info.Column(column); !ok || col.PrimaryKey || col.Unique {
+		return false
+	}
+	
+	for _, index := range info.Indexes {
+		for _, indexed := range index.Columns {
+			if indexed == column {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// sqliteSupportsDropColumn indica se a versão do SQLite tem DROP COLUMN (3.35+)
+func (m *Migrator) sqliteSupportsDropColumn(ctx context.Context) (bool, error) {
+	var version string
+	if err := m.db.QueryRowContext(ctx, "SELECT sqlite_version()").Scan(&version); err != nil {
+		return false, err
+	}
+	
+	var major, minor int
+	if _, err := fmt.Sscanf(version, "%d.%d", &major, &minor); err != nil {
+		return false, fmt.Errorf("versão do SQLite inválida %q: %v", version, err)
+	}
+	return major > 3 || (major == 3 && minor >= 35), nil
+}
+
+// sqliteForeignKeys indica se a verificação de chaves estrangeiras está ativa
+func (m *Migrator) sqliteForeignKeys(ctx context.Context) (bool, error) {
+	var enabled bool
+	err := m.db.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&enabled)
+	return enabled, err
+}
+
+// sqliteObjects lista os índices e triggers da tabela que têm SQL de criação
+func (m *Migrator) sqliteObjects(ctx context.Context, table string) ([]sqliteObject, error) {
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT type, name, sql
+		FROM sqlite_master
+		WHERE tbl_name = ?
+		AND type IN ('index', 'trigger')
+		AND sql IS NOT NULL
+		ORDER BY type, name
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var objects []sqliteObject
+	for rows.Next() {
+		var object sqliteObject
+		if err := rows.Scan(&object.Type, &object.Name, &object.SQL); err != nil {
+			return nil, err
+		}
+		objects = append(objects, object)
+	}
+	return objects, rows.Err()
+}
@@ -0,0 +1,369 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// VersionsTable é a tabela que registra as migrações versionadas aplicadas
+const VersionsTable = "schema_migrations"
+
+// Conn é a conexão recebida pelas migrações: *sql.Tx quando o dialeto
+// suporta DDL transacional, ou *sql.DB caso contrário
+type Conn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// MigrationFunc aplica ou desfaz uma migração
+type MigrationFunc func(ctx context.Context, conn Conn) error
+
+// VersionedMigration é uma migração escrita à mão, identificada pela versão
+type VersionedMigration struct {
+	Version int64
+	Name    string
+	Up      MigrationFunc
+	Down    MigrationFunc // nil torna a migração irreversível
+	
+	// Comandos das migrações registradas com RegisterSQL, usados no plano
+	UpSQL   []string
+	DownSQL []string
+	
+	// Checksum identifica o conteúdo da migração. RegisterSQL o calcula a partir
+	// dos comandos; migrações Go o recebem em RegisterChecksum. Vazio não é verificado.
+	Checksum string
+}
+
+// MigrationStatus informa se uma migração registrada foi aplicada
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	Modified  bool // O checksum atual difere do registrado ao aplicar
+}
+
+// appliedVersion é o registro de uma migração na tabela de versões
+type appliedVersion struct {
+	at       time.Time
+	checksum string
+}
+
+// Register registra uma migração versionada sem checksum: alterações no código
+// depois de aplicada não são detectadas. Use RegisterChecksum para verificá-la.
+func (m *Migrator) Register(version int64, name string, up, down MigrationFunc) error {
+	if up == nil {
+		return fmt.Errorf("migração %d (%s) sem função up", version, name)
+	}
+	
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return fmt.Errorf("versão %d registrada em duplicidade: %s e %s", version, migration.Name, name)
+		}
+	}
+	
+	m.migrations = append(m.migrations, &VersionedMigration{
+		Version: version,
+		Name:    name,
+		Up:      up,
+		Down:    down,
+	})
+	
+	sort.Slice(m.migrations, func(i, j int) bool {
+		return m.migrations[i].Version < m.migrations[j].Version
+	})
+	return nil
+}
+
+// RegisterChecksum registra uma migração Go com o checksum informado, que deve
+// mudar junto com o código da migração (ex.: um hash ou uma revisão)
+func (m *Migrator) RegisterChecksum(version int64, name, checksum string, up, down MigrationFunc) error {
+	if checksum == "" || len(checksum) > 64 {
+		return fmt.Errorf("migração %d (%s) com checksum inválido: %q", version, name, checksum)
+	}
+	if err := m.Register(version, name, up, down); err != nil {
+		return err
+	}
+	
+	m.migration(version).Checksum = checksum
+	return nil
+}
+
+// Migrations retorna as migrações registradas em ordem de versão
+func (m *Migrator) Migrations() []*VersionedMigration {
+	return append([]*VersionedMigration(nil), m.migrations...)
+}
+
+// MigrateUp aplica, em ordem de versão, as migrações registradas ainda não aplicadas
+func (m *Migrator) MigrateUp(ctx context.Context) error {
+	return m.withLock(ctx, func(ctx context.Context) error {
+		if err := m.ensureVersionsTable(ctx); err != nil {
+			return fmt.Errorf("erro ao criar tabela de versões: %w", err)
+		}
+		if err := m.checkChecksums(ctx); err != nil {
+			return err
+		}
+		
+		applied, err := m.appliedVersions(ctx)
+		if err != nil {
+			return err
+		}
+		
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, migration, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Rollback desfaz as n últimas migrações aplicadas, da mais recente para a mais antiga
+func (m *Migrator) Rollback(ctx context.Context, n int) error {
+	return m.withLock(ctx, func(ctx context.Context) error {
+		return m.rollback(ctx, n)
+	})
+}
+
+// rollback desfaz as n últimas migrações aplicadas sem obter o lock
+func (m *Migrator) rollback(ctx context.Context, n int) error {
+	versions, err := m.lastApplied(ctx, n)
+	if err != nil {
+		return err
+	}
+	
+	for _, version := range versions {
+		migration, err := m.reversible(version)
+		if err != nil {
+			return err
+		}
+		if err := m.apply(ctx, migration, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lastApplied retorna as n últimas versões aplicadas, da mais recente para a mais antiga
+func (m *Migrator) lastApplied(ctx context.Context, n int) ([]int64, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	versions := make([]int64, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+	
+	if n > len(versions) {
+		n = len(versions)
+	}
+	return versions[:n], nil
+}
+
+// reversible retorna a migração registrada com a versão, se ela puder ser desfeita
+func (m *Migrator) reversible(version int64) (*VersionedMigration, error) {
+	migration := m.migration(version)
+	if migration == nil {
+		return nil, fmt.Errorf("migração %d aplicada não está registrada", version)
+	}
+	if migration.Down == nil {
+		return nil, fmt.Errorf("migração %d (%s) é irreversível", version, migration.Name)
+	}
+	return migration, nil
+}
+
+// Redo desfaz e reaplica a última migração aplicada
+func (m *Migrator) Redo(ctx context.Context) error {
+	return m.withLock(ctx, func(ctx context.Context) error {
+		if err := m.ensureVersionsTable(ctx); err != nil {
+			return fmt.Errorf("erro ao criar tabela de versões: %w", err)
+		}
+		
+		last, err := m.lastApplied(ctx, 1)
+		if err != nil {
+			return err
+		}
+		if len(last) == 0 {
+			return fmt.Errorf("nenhuma migração aplicada")
+		}
+		
+		if err := m.rollback(ctx, 1); err != nil {
+			return err
+		}
+		return m.apply(ctx, m.migration(last[0]), true)
+	})
+}
+
+// Status lista as migrações registradas e as aplicadas, pendentes ou não
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &record.at
+			status.Modified = checksumChanged(migration, record)
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	
+	// Migrações aplicadas que não estão mais registradas
+	for version, record := range applied {
+		record := record
+		statuses = append(statuses, MigrationStatus{Version: version, Applied: true, AppliedAt: &record.at})
+	}
+	
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// migration retorna a migração registrada com a versão
+func (m *Migrator) migration(version int64) *VersionedMigration {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration
+		}
+	}
+	return nil
+}
+
+// apply executa up ou down e atualiza a tabela de versões. Em dialetos com DDL
+// transacional, tudo ocorre em uma única transação.
+func (m *Migrator) apply(ctx context.Context, migration *VersionedMigration, up bool) error {
+	direction, fn := "up", migration.Up
+	if !up {
+		direction, fn = "down", migration.Down
+	}
+	
+	run := func(conn Conn) error {
+		if err := fn(ctx, conn); err != nil {
+			return fmt.Errorf("erro na migração %d (%s) %s: %w", migration.Version, migration.Name, direction, err)
+		}
+		if up {
+			return m.recordVersion(ctx, conn, migration)
+		}
+		return m.removeVersion(ctx, conn, migration.Version)
+	}
+	
+	if !m.dialect.SupportsTransactionalDDL() {
+		return run(m.db)
+	}
+	
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	
+	if err := run(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// ensureVersionsTable garante que a tabela de versões existe, com a coluna
+// checksum. Só as operações que aplicam migrações a chamam, dentro do lock.
+func (m *Migrator) ensureVersionsTable(ctx context.Context) error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP NOT NULL,
+			checksum VARCHAR(64)
+		)
+	`, m.dialect.Quote(VersionsTable))
+	
+	if _, err := m.db.ExecContext(ctx, query); err != nil {
+		return err
+	}
+	
+	// Tabelas criadas antes do registro de checksums ganham a coluna
+	columns, err := m.introspectColumns(ctx, VersionsTable)
+	if err != nil {
+		return err
+	}
+	for _, column := range columns {
+		if column.Name == "checksum" {
+			return nil
+		}
+	}
+	
+	_, err = m.db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN checksum VARCHAR(64)", m.dialect.Quote(VersionsTable)))
+	return err
+}
+
+// appliedVersions retorna as versões aplicadas, quando foram aplicadas e seus
+// checksums. Não altera o banco: sem a tabela de versões, nada foi aplicado, e
+// sem a coluna checksum os registros são lidos sem checksum.
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int64]appliedVersion, error) {
+	columns, err := m.introspectColumns(ctx, VersionsTable)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return map[int64]appliedVersion{}, nil
+	}
+	
+	checksumColumn := "NULL"
+	for _, column := range columns {
+		if column.Name == "checksum" {
+			checksumColumn = "checksum"
+		}
+	}
+	
+	query := fmt.Sprintf("SELECT version, applied_at, %s FROM %s", checksumColumn, m.dialect.Quote(VersionsTable))
+	rows, err := m.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	applied := make(map[int64]appliedVersion)
+	for rows.Next() {
+		var version int64
+		var at time.Time
+		var checksum sql.NullString
+		if err := rows.Scan(&version, &at, &checksum); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedVersion{at: at, checksum: checksum.String}
+	}
+	
+	return applied, rows.Err()
+}
+
+// recordVersion registra a migração como aplicada
+func (m *Migrator) recordVersion(ctx context.Context, conn Conn, migration *VersionedMigration) error {
+	query := fmt.Sprintf("INSERT INTO %s (version, name, applied_at, checksum) VALUES (%s, %s, %s, %s)",
+		m.dialect.Quote(VersionsTable),
+		m.dialect.Placeholder(1), m.dialect.Placeholder(2), m.dialect.Placeholder(3), m.dialect.Placeholder(4))
+	
+	checksum := sql.NullString{String: migration.Checksum, Valid: migration.Checksum != ""}
+	_, err := conn.ExecContext(ctx, query, migration.Version, migration.Name, time.Now().UTC(), checksum)
+	return err
+}
+
+// removeVersion remove o registro de uma migração desfeita
+func (m *Migrator) removeVersion(ctx context.Context, conn Conn, version int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE version = %s",
+		m.dialect.Quote(VersionsTable), m.dialect.Placeholder(1))
+	
+	_, err := conn.ExecContext(ctx, query, version)
+	return err
+}
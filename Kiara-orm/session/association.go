@@ -0,0 +1,720 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	
+	"github.com/Flavio-coutinho/Kiara-orm/hooks"
+	"github.com/Flavio-coutinho/Kiara-orm/relation"
+	"github.com/Flavio-coutinho/Kiara-orm/schema"
+	"github.com/Flavio-coutinho/Kiara-orm/softdelete"
+	"github.com/Flavio-coutinho/Kiara-orm/types"
+)
+
+// Association manipula os registros ligados a um modelo por um relacionamento,
+// mantendo tabelas de junção e chaves estrangeiras. As operações rodam na
+// transação da sessão ou, se não houver uma, em uma nova transação.
+type Association struct {
+	handler  *ModelHandler
+	field    string
+	relation relation.Relation
+	err      error
+}
+
+// Association retorna o manipulador de um relacionamento do registro passado em Model,
+// por exemplo sess.Model(&post).Association("Tags")
+func (m *ModelHandler) Association(field string) *Association {
+	assoc := &Association{handler: m, field: field}
+	if m.err != nil {
+		assoc.err = m.err
+		return assoc
+	}
+	
+	v := reflect.ValueOf(m.model)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		assoc.err = fmt.Errorf("Association requer um ponteiro para struct")
+		return assoc
+	}
+	
+	rel, ok := m.session.relations.GetRelation(m.model, field)
+	if !ok {
+		assoc.err = fmt.Errorf("relacionamento %s não definido para %s", field, m.mapping.TableName)
+		return assoc
+	}
+	
+	assoc.relation = rel
+	return assoc
+}
+
+// Append associa os registros ao modelo. Registros sem chave primária são criados.
+// Em HasOne e BelongsTo o registro informado substitui o atual.
+func (a *Association) Append(ctx context.Context, records ...interface{}) error {
+	if a.err != nil {
+		return a.err
+	}
+	
+	err := a.run(ctx, hooks.BeforeAssociate, hooks.AfterAssociate, records, func(s *Session) error {
+		if a.relation.Type == relation.OneToOne {
+			if err := a.clear(ctx, s); err != nil {
+				return err
+			}
+		}
+		return a.link(ctx, s, records)
+	})
+	if err != nil {
+		return err
+	}
+	
+	if a.relation.Type == relation.OneToOne || a.relation.Type == relation.BelongsTo {
+		a.setField(records)
+	} else {
+		a.appendField(records)
+	}
+	return nil
+}
+
+// Replace substitui todos os registros associados pelos informados. Sem
+// registros, equivale a Clear.
+func (a *Association) Replace(ctx context.Context, records ...interface{}) error {
+	if a.err != nil {
+		return a.err
+	}
+	
+	if len(records) == 0 {
+		return a.Clear(ctx)
+	}
+	
+	err := a.run(ctx, hooks.BeforeAssociate, hooks.AfterAssociate, records, func(s *Session) error {
+		if err := a.clear(ctx, s); err != nil {
+			return err
+		}
+		return a.link(ctx, s, records)
+	})
+	if err != nil {
+		return err
+	}
+	
+	a.setField(records)
+	return nil
+}
+
+// Delete remove a associação com os registros informados, sem apagar os registros.
+// Em HasOne/HasMany a chave estrangeira é anulada (ou zerada se a coluna não aceita NULL).
+func (a *Association) Delete(ctx context.Context, records ...interface{}) error {
+	if a.err != nil {
+		return a.err
+	}
+	
+	if len(records) == 0 {
+		return nil
+	}
+	
+	err := a.run(ctx, hooks.BeforeDissociate, hooks.AfterDissociate, records, func(s *Session) error {
+		return a.unlink(ctx, s, records)
+	})
+	if err != nil {
+		return err
+	}
+	
+	a.removeFromField(records)
+	return nil
+}
+
+// Clear remove todas as associações do relacionamento
+func (a *Association) Clear(ctx context.Context) error {
+	if a.err != nil {
+		return a.err
+	}
+	
+	err := a.run(ctx, hooks.BeforeDissociate, hooks.AfterDissociate, nil, func(s *Session) error {
+		return a.clear(ctx, s)
+	})
+	if err != nil {
+		return err
+	}
+	
+	a.setField(nil)
+	return nil
+}
+
+// Count retorna o número de registros associados
+func (a *Association) Count(ctx context.Context) (int64, error) {
+	if a.err != nil {
+		return 0, a.err
+	}
+	
+	s := a.handler.session
+	owner := a.owner()
+	rel := a.relation
+	
+	var query string
+	var args []interface{}
+	
+	switch rel.Type {
+	case relation.ManyToMany:
+		related, err := a.relatedMapping()
+		if err != nil {
+			return 0, err
+		}
+		assocColumn, err := columnFor(related, rel.AssociationKey)
+		if err != nil {
+			return 0, err
+		}
+		
+		// A junção com a tabela relacionada exclui os registros excluídos logicamente
+		d := s.dialect
+		query = fmt.Sprintf("SELECT COUNT(*) FROM %s INNER JOIN %s ON %s.%s = %s.%s WHERE %s.%s = ?",
+			d.Quote(rel.JoinTable), d.Quote(related.TableName),
+			d.Quote(rel.JoinTable), d.Quote(rel.JoinReferenceKey),
+			d.Quote(related.TableName), d.Quote(assocColumn),
+			d.Quote(rel.JoinTable), d.Quote(rel.JoinForeignKey))
+		args = append(args, owner.FieldByName(rel.ReferenceKey).Interface())
+		
+		if softdelete.HasColumn(related) {
+			query += fmt.Sprintf(" AND %s.%s IS NULL", d.Quote(related.TableName), d.Quote(softdelete.Column))
+		}
+	
+	default:
+		related, err := a.relatedMapping()
+		if err != nil {
+			return 0, err
+		}
+		
+		// Em BelongsTo a contagem é feita pela chave referenciada do registro relacionado
+		keyField, ownerField := rel.ForeignKey, rel.ReferenceKey
+		if rel.Type == relation.BelongsTo {
+			keyField, ownerField = rel.ReferenceKey, rel.ForeignKey
+		}
+		
+		column, err := columnFor(related, keyField)
+		if err != nil {
+			return 0, err
+		}
+		
+		query = fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s = ?",
+			s.dialect.Quote(related.TableName), s.dialect.Quote(column))
+		args = append(args, owner.FieldByName(ownerField).Interface())
+		
+		if rel.IsPolymorphic() {
+			typeColumn, err := columnFor(related, rel.PolymorphicType)
+			if err != nil {
+				return 0, err
+			}
+			query += fmt.Sprintf(" AND %s = ?", s.dialect.Quote(typeColumn))
+			args = append(args, rel.PolymorphicValue)
+		}
+		if softdelete.HasColumn(related) {
+			query += fmt.Sprintf(" AND %s IS NULL", s.dialect.Quote(softdelete.Column))
+		}
+	}
+	
+	var count int64
+	err := s.conn(ctx).QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+// run executa a operação com os hooks de associação dentro de uma transação
+func (a *Association) run(ctx context.Context, before, after hooks.HookType, records []interface{}, fn func(s *Session) error) error {
+	change := &hooks.AssociationChange{
+		Owner:   a.handler.model,
+		Field:   a.field,
+		Related: records,
+	}
+	
+	exec := func(ctx context.Context, s *Session) error {
+		if err := s.hooks.Execute(ctx, before, change); err != nil {
+			return err
+		}
+		if err := fn(s); err != nil {
+			return err
+		}
+		return s.hooks.Execute(ctx, after, change)
+	}
+	
+	if active := a.handler.session.active(ctx); active.tx != nil {
+		return exec(ctx, active)
+	}
+	return a.handler.session.Transaction(ctx, func(tx *Session) error {
+		return exec(tx.Context(), tx)
+	})
+}
+
+// link grava a associação com os registros
+func (a *Association) link(ctx context.Context, s *Session, records []interface{}) error {
+	rel := a.relation
+	owner := a.owner()
+	
+	related, err := a.relatedMapping()
+	if err != nil {
+		return err
+	}
+	
+	switch rel.Type {
+	case relation.ManyToMany:
+		ownerKey := owner.FieldByName(rel.ReferenceKey).Interface()
+		
+		existing, err := a.joinedKeys(ctx, s, ownerKey)
+		if err != nil {
+			return err
+		}
+		
+		insert := fmt.Sprintf("INSERT INTO %s (%s, %s) VALUES (?, ?)",
+			s.dialect.Quote(rel.JoinTable),
+			s.dialect.Quote(rel.JoinForeignKey),
+			s.dialect.Quote(rel.JoinReferenceKey))
+		
+		for _, record := range records {
+			if err := a.ensureSaved(ctx, s, related, record); err != nil {
+				return err
+			}
+			
+			key := reflect.Indirect(reflect.ValueOf(record)).FieldByName(rel.AssociationKey).Interface()
+			if existing[keyOf(key)] {
+				continue
+			}
+			existing[keyOf(key)] = true
+			
+			if _, err := s.conn(ctx).ExecContext(ctx, insert, ownerKey, key); err != nil {
+				return err
+			}
+		}
+	
+	case relation.OneToOne, relation.OneToMany:
+		fk, err := fieldFor(related, rel.ForeignKey)
+		if err != nil {
+			return err
+		}
+		
+		typeField, err := a.typeField(related)
+		if err != nil {
+			return err
+		}
+		
+		columns := []string{fk.Name}
+		values := []interface{}{owner.FieldByName(rel.ReferenceKey).Interface()}
+		if typeField != nil {
+			columns = append(columns, typeField.Name)
+			values = append(values, rel.PolymorphicValue)
+		}
+		
+		for _, record := range records {
+			item := reflect.Indirect(reflect.ValueOf(record))
+			assignValue(item.FieldByName(rel.ForeignKey), values[0])
+			if typeField != nil {
+				assignValue(item.FieldByName(rel.PolymorphicType), rel.PolymorphicValue)
+			}
+			
+			// Registros novos já são criados com a chave estrangeira
+			created, err := a.createIfNew(ctx, s, related, record)
+			if err != nil {
+				return err
+			}
+			if created {
+				continue
+			}
+			
+			if err := updateColumns(ctx, s, related, record, columns, values); err != nil {
+				return err
+			}
+		}
+	
+	case relation.BelongsTo:
+		if len(records) != 1 {
+			return fmt.Errorf("BelongsTo aceita exatamente um registro, recebidos %d", len(records))
+		}
+		
+		if err := a.ensureSaved(ctx, s, related, records[0]); err != nil {
+			return err
+		}
+		
+		fk, err := fieldFor(a.handler.mapping, rel.ForeignKey)
+		if err != nil {
+			return err
+		}
+		
+		key := reflect.Indirect(reflect.ValueOf(records[0])).FieldByName(rel.ReferenceKey).Interface()
+		assignValue(owner.FieldByName(rel.ForeignKey), key)
+		return updateColumns(ctx, s, a.handler.mapping, a.handler.model, []string{fk.Name}, []interface{}{key})
+	}
+	
+	return nil
+}
+
+// unlink desfaz a associação com os registros informados
+func (a *Association) unlink(ctx context.Context, s *Session, records []interface{}) error {
+	rel := a.relation
+	owner := a.owner()
+	
+	related, err := a.relatedMapping()
+	if err != nil {
+		return err
+	}
+	
+	switch rel.Type {
+	case relation.ManyToMany:
+		keys := recordKeys(records, rel.AssociationKey)
+		query := fmt.Sprintf("DELETE FROM %s WHERE %s = ? AND %s IN (%s)",
+			s.dialect.Quote(rel.JoinTable),
+			s.dialect.Quote(rel.JoinForeignKey),
+			s.dialect.Quote(rel.JoinReferenceKey),
+			placeholders(len(keys)))
+		
+		args := append([]interface{}{owner.FieldByName(rel.ReferenceKey).Interface()}, keys...)
+		_, err := s.conn(ctx).ExecContext(ctx, query, args...)
+		return err
+	
+	case relation.OneToOne, relation.OneToMany:
+		fk, err := fieldFor(related, rel.ForeignKey)
+		if err != nil {
+			return err
+		}
+		
+		pk, ok := primaryKeyOf(related)
+		if !ok {
+			return fmt.Errorf("tabela %s não possui chave primária", related.TableName)
+		}
+		
+		set, where, args, err := a.detachClause(s, related, fk)
+		if err != nil {
+			return err
+		}
+		
+		keys := recordKeys(records, pk.FieldName)
+		query := fmt.Sprintf("UPDATE %s SET %s WHERE %s AND %s IN (%s)",
+			s.dialect.Quote(related.TableName),
+			set,
+			where,
+			s.dialect.Quote(pk.Name),
+			placeholders(len(keys)))
+		
+		if _, err := s.conn(ctx).ExecContext(ctx, query, append(args, keys...)...); err != nil {
+			return err
+		}
+		
+		for _, record := range records {
+			item := reflect.Indirect(reflect.ValueOf(record))
+			assignValue(item.FieldByName(rel.ForeignKey), nil)
+			if rel.IsPolymorphic() {
+				assignValue(item.FieldByName(rel.PolymorphicType), nil)
+			}
+		}
+		return nil
+	
+	case relation.BelongsTo:
+		return a.clear(ctx, s)
+	}
+	
+	return nil
+}
+
+// clear desfaz todas as associações do relacionamento
+func (a *Association) clear(ctx context.Context, s *Session) error {
+	rel := a.relation
+	owner := a.owner()
+	
+	switch rel.Type {
+	case relation.ManyToMany:
+		query := fmt.Sprintf("DELETE FROM %s WHERE %s = ?",
+			s.dialect.Quote(rel.JoinTable),
+			s.dialect.Quote(rel.JoinForeignKey))
+		_, err := s.conn(ctx).ExecContext(ctx, query, owner.FieldByName(rel.ReferenceKey).Interface())
+		return err
+	
+	case relation.OneToOne, relation.OneToMany:
+		related, err := a.relatedMapping()
+		if err != nil {
+			return err
+		}
+		
+		fk, err := fieldFor(related, rel.ForeignKey)
+		if err != nil {
+			return err
+		}
+		
+		set, where, args, err := a.detachClause(s, related, fk)
+		if err != nil {
+			return err
+		}
+		
+		query := fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+			s.dialect.Quote(related.TableName), set, where)
+		_, err = s.conn(ctx).ExecContext(ctx, query, args...)
+		return err
+	
+	case relation.BelongsTo:
+		fk, err := fieldFor(a.handler.mapping, rel.ForeignKey)
+		if err != nil {
+			return err
+		}
+		
+		empty := emptyKey(fk, a.handler.model)
+		if err := updateColumns(ctx, s, a.handler.mapping, a.handler.model, []string{fk.Name}, []interface{}{empty}); err != nil {
+			return err
+		}
+		assignValue(owner.FieldByName(rel.ForeignKey), nil)
+	}
+	
+	return nil
+}
+
+// detachClause monta o SET e o WHERE que desassociam os registros de um HasOne/HasMany.
+// Em relacionamentos polimórficos a coluna de tipo também é filtrada e limpa.
+func (a *Association) detachClause(s *Session, related *schema.TableMapping, fk types.FieldMapping) (string, string, []interface{}, error) {
+	typeField, err := a.typeField(related)
+	if err != nil {
+		return "", "", nil, err
+	}
+	
+	set := fmt.Sprintf("%s = ?", s.dialect.Quote(fk.Name))
+	where := fmt.Sprintf("%s = ?", s.dialect.Quote(fk.Name))
+	setArgs := []interface{}{emptyKey(fk, a.relation.Model)}
+	whereArgs := []interface{}{a.owner().FieldByName(a.relation.ReferenceKey).Interface()}
+	
+	if typeField != nil {
+		set += fmt.Sprintf(", %s = ?", s.dialect.Quote(typeField.Name))
+		where += fmt.Sprintf(" AND %s = ?", s.dialect.Quote(typeField.Name))
+		setArgs = append(setArgs, emptyKey(*typeField, a.relation.Model))
+		whereArgs = append(whereArgs, a.relation.PolymorphicValue)
+	}
+	
+	return set, where, append(setArgs, whereArgs...), nil
+}
+
+// typeField retorna o campo de tipo de um relacionamento polimórfico, ou nil
+func (a *Association) typeField(related *schema.TableMapping) (*types.FieldMapping, error) {
+	if !a.relation.IsPolymorphic() {
+		return nil, nil
+	}
+	
+	field, err := fieldFor(related, a.relation.PolymorphicType)
+	if err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
+// joinedKeys retorna as chaves já associadas ao dono na tabela de junção
+func (a *Association) joinedKeys(ctx context.Context, s *Session, ownerKey interface{}) (map[string]bool, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?",
+		s.dialect.Quote(a.relation.JoinReferenceKey),
+		s.dialect.Quote(a.relation.JoinTable),
+		s.dialect.Quote(a.relation.JoinForeignKey))
+	
+	rows, err := s.conn(ctx).QueryContext(ctx, query, ownerKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	keys := make(map[string]bool)
+	for rows.Next() {
+		var key interface{}
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys[keyOf(key)] = true
+	}
+	return keys, rows.Err()
+}
+
+// ensureSaved cria o registro relacionado se ele ainda não tiver chave primária
+func (a *Association) ensureSaved(ctx context.Context, s *Session, mapping *schema.TableMapping, record interface{}) error {
+	_, err := a.createIfNew(ctx, s, mapping, record)
+	return err
+}
+
+// createIfNew cria o registro se a chave primária estiver vazia e indica se houve criação
+func (a *Association) createIfNew(ctx context.Context, s *Session, mapping *schema.TableMapping, record interface{}) (bool, error) {
+	pk, ok := primaryKeyOf(mapping)
+	if !ok || !reflect.Indirect(reflect.ValueOf(record)).FieldByName(pk.FieldName).IsZero() {
+		return false, nil
+	}
+	
+	return true, s.Model(a.relation.Model).Create(ctx, record)
+}
+
+// owner retorna a struct dona do relacionamento
+func (a *Association) owner() reflect.Value {
+	return reflect.ValueOf(a.handler.model).Elem()
+}
+
+// relatedMapping retorna o mapeamento do modelo relacionado
+func (a *Association) relatedMapping() (*schema.TableMapping, error) {
+	return schema.NewParser().Parse(a.relation.Model)
+}
+
+// setField substitui o conteúdo do campo de relacionamento em memória
+func (a *Association) setField(records []interface{}) {
+	target := a.owner().FieldByName(a.field)
+	
+	if target.Kind() == reflect.Slice {
+		target.Set(reflect.MakeSlice(target.Type(), 0, len(records)))
+		a.appendField(records)
+		return
+	}
+	
+	target.Set(reflect.Zero(target.Type()))
+	if len(records) > 0 {
+		assignOne(target, reflect.ValueOf(records[0]))
+	}
+}
+
+// appendField adiciona registros ao campo de relacionamento em memória
+func (a *Association) appendField(records []interface{}) {
+	target := a.owner().FieldByName(a.field)
+	if target.Kind() != reflect.Slice {
+		return
+	}
+	
+	// Registros já presentes no campo não são duplicados
+	keyField := a.keyField()
+	present := make(map[string]bool)
+	for i := 0; i < target.Len(); i++ {
+		present[keyOf(reflect.Indirect(target.Index(i)).FieldByName(keyField).Interface())] = true
+	}
+	
+	for _, record := range records {
+		key := keyOf(reflect.Indirect(reflect.ValueOf(record)).FieldByName(keyField).Interface())
+		if present[key] {
+			continue
+		}
+		present[key] = true
+		appendMany(target, reflect.ValueOf(record))
+	}
+}
+
+// removeFromField remove registros do campo de relacionamento em memória
+func (a *Association) removeFromField(records []interface{}) {
+	target := a.owner().FieldByName(a.field)
+	if target.Kind() != reflect.Slice {
+		target.Set(reflect.Zero(target.Type()))
+		return
+	}
+	
+	keyField := a.keyField()
+	removed := make(map[string]bool)
+	for _, key := range recordKeys(records, keyField) {
+		removed[keyOf(key)] = true
+	}
+	
+	kept := reflect.MakeSlice(target.Type(), 0, target.Len())
+	for i := 0; i < target.Len(); i++ {
+		item := reflect.Indirect(target.Index(i))
+		if !removed[keyOf(item.FieldByName(keyField).Interface())] {
+			kept = reflect.Append(kept, target.Index(i))
+		}
+	}
+	target.Set(kept)
+}
+
+// keyField retorna o campo que identifica os registros relacionados
+func (a *Association) keyField() string {
+	if a.relation.Type == relation.ManyToMany {
+		return a.relation.AssociationKey
+	}
+	
+	if related, err := a.relatedMapping(); err == nil {
+		if pk, ok := primaryKeyOf(related); ok {
+			return pk.FieldName
+		}
+	}
+	return "ID"
+}
+
+// Funções auxiliares de associação
+
+// updateColumns atualiza colunas de um registro identificado pela chave primária
+func updateColumns(ctx context.Context, s *Session, mapping *schema.TableMapping, record interface{}, columns []string, values []interface{}) error {
+	pk, ok := primaryKeyOf(mapping)
+	if !ok {
+		return fmt.Errorf("tabela %s não possui chave primária", mapping.TableName)
+	}
+	
+	sets := make([]string, len(columns))
+	for i, column := range columns {
+		sets[i] = fmt.Sprintf("%s = ?", s.dialect.Quote(column))
+	}
+	
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s = ?",
+		s.dialect.Quote(mapping.TableName),
+		strings.Join(sets, ", "),
+		s.dialect.Quote(pk.Name))
+	
+	id := reflect.Indirect(reflect.ValueOf(record)).FieldByName(pk.FieldName).Interface()
+	_, err := s.conn(ctx).ExecContext(ctx, query, append(values, id)...)
+	return err
+}
+
+// primaryKeyOf retorna o campo de chave primária do mapeamento
+func primaryKeyOf(mapping *schema.TableMapping) (types.FieldMapping, bool) {
+	for _, field := range mapping.Fields {
+		if field.IsPrimaryKey {
+			return field, true
+		}
+	}
+	return types.FieldMapping{}, false
+}
+
+// fieldFor retorna o mapeamento de um campo da struct
+func fieldFor(mapping *schema.TableMapping, fieldName string) (types.FieldMapping, error) {
+	for _, field := range mapping.Fields {
+		if field.FieldName == fieldName {
+			return field, nil
+		}
+	}
+	return types.FieldMapping{}, fmt.Errorf("campo %s não encontrado em %s", fieldName, mapping.TableName)
+}
+
+// emptyKey retorna o valor usado para desassociar: NULL se a coluna aceita, ou o zero do tipo
+func emptyKey(field types.FieldMapping, record interface{}) interface{} {
+	if field.IsNullable {
+		return nil
+	}
+	
+	t := reflect.TypeOf(record)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	
+	if f, ok := t.FieldByName(field.FieldName); ok {
+		return reflect.Zero(f.Type).Interface()
+	}
+	return nil
+}
+
+// recordKeys retorna os valores de um campo dos registros
+func recordKeys(records []interface{}, fieldName string) []interface{} {
+	keys := make([]interface{}, 0, len(records))
+	for _, record := range records {
+		keys = append(keys, reflect.Indirect(reflect.ValueOf(record)).FieldByName(fieldName).Interface())
+	}
+	return keys
+}
+
+// assignValue atribui um valor a um campo convertendo o tipo; nil zera o campo
+func assignValue(field reflect.Value, value interface{}) {
+	if !field.IsValid() || !field.CanSet() {
+		return
+	}
+	
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return
+	}
+	
+	v := reflect.Indirect(reflect.ValueOf(value))
+	if field.Kind() == reflect.Ptr {
+		ptr := reflect.New(field.Type().Elem())
+		ptr.Elem().Set(v.Convert(field.Type().Elem()))
+		field.Set(ptr)
+		return
+	}
+	field.Set(v.Convert(field.Type()))
+}
+
+// placeholders retorna uma lista de n placeholders separados por vírgula
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
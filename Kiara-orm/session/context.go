@@ -0,0 +1,53 @@
+package session
+
+import "context"
+
+// txContextKey é a chave da sessão transacional no contexto
+type txContextKey struct{}
+
+// ContextWithTx retorna um contexto que carrega a sessão transacional tx.
+// Operações de sessões do mesmo banco que recebem esse contexto participam da transação.
+func ContextWithTx(ctx context.Context, tx *Session) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+// TxFromContext retorna a sessão transacional carregada pelo contexto
+func TxFromContext(ctx context.Context) (*Session, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(*Session)
+	return tx, ok && tx != nil && tx.tx != nil
+}
+
+// WithoutTx retorna um contexto que ignora a transação do contexto pai,
+// para operações que devem ser executadas fora dela
+func WithoutTx(ctx context.Context) context.Context {
+	return context.WithValue(ctx, txContextKey{}, (*Session)(nil))
+}
+
+// active retorna a sessão usada pelas operações: a própria sessão se ela já está
+// em transação, ou a transação do contexto quando pertence ao mesmo banco
+func (s *Session) active(ctx context.Context) *Session {
+	if s.tx != nil || ctx == nil {
+		return s
+	}
+	if tx, ok := TxFromContext(ctx); ok && tx.db == s.db {
+		return tx
+	}
+	return s
+}
+
+// Context retorna o contexto da transação da sessão, que pode ser repassado às
+// camadas de serviço. Fora de transação retorna context.Background().
+func (s *Session) Context() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return context.Background()
+}
+
+// TransactionContext executa fn em uma transação recebendo o contexto que a carrega,
+// para código que trabalha apenas com context.Context
+func (s *Session) TransactionContext(ctx context.Context, fn func(ctx context.Context) error) error {
+	return s.Transaction(ctx, func(tx *Session) error {
+		return fn(tx.Context())
+	})
+}
@@ -3,6 +3,7 @@ package session
 import (
 	"context"
 	"database/sql"
+	"time"
 	
 	"github.com/Flavio-coutinho/Kiara-orm/dialect"
 	"github.com/Flavio-coutinho/Kiara-orm/query"
@@ -14,8 +15,16 @@ import (
 	"github.com/Flavio-coutinho/Kiara-orm/validator"
 	"github.com/Flavio-coutinho/Kiara-orm/relation"
 	"github.com/Flavio-coutinho/Kiara-orm/metrics"
+	"github.com/Flavio-coutinho/Kiara-orm/timestamp"
 )
 
+// dbConn abstrai *sql.DB e *sql.Tx
+type dbConn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
 // Session representa uma sessão de banco de dados
 type Session struct {
 	db        *sql.DB
@@ -29,6 +38,9 @@ type Session struct {
 	validator *validator.Validator
 	relations *relation.RelationManager
 	metrics *metrics.Collector
+	clock     timestamp.Clock
+	txOptions transaction.Options // Padrão fora de transação; opções ativas dentro dela
+	ctx       context.Context     // Contexto que carrega a transação da sessão
 }
 
 // NewSession cria uma nova sessão
@@ -37,17 +49,21 @@ func NewSession(db *sql.DB, dialect dialect.Dialect) *Session {
 		db:        db,
 		dialect:   dialect,
 		migrator:  schema.NewMigrator(db, dialect),
-		txManager: transaction.NewTxManager(db),
+		txManager: transaction.NewTxManager(db, dialect),
 		cache:     cache.NewCache(),
 		hooks:     hooks.NewHookManager(),
 		logger:    logger.NewDefaultLogger(logger.INFO),
 		validator: validator.NewValidator(),
-		relations: relation.NewRelationManager(),
+		relations: relation.NewRelationManager(schema.NewParser().Parse),
 		metrics: metrics.NewCollector(),
+		clock:     time.Now,
 	}
 	
 	// Adiciona exportador Prometheus por padrão
 	session.metrics.AddExporter(metrics.NewPrometheusExporter())
+	session.txManager.SetMetrics(session.metrics)
+	session.txManager.SetLogger(session.logger)
+	session.migrator.SetLogger(session.logger)
 	
 	return session
 }
@@ -65,36 +81,146 @@ func (s *Session) Exec(builder *query.Builder) *query.Executor {
 	return query.NewExecutor(s.db, builder)
 }
 
+// ExecContext cria um novo executor que usa a transação do contexto, se houver
+func (s *Session) ExecContext(ctx context.Context, builder *query.Builder) *query.Executor {
+	return s.active(ctx).Exec(builder)
+}
+
+// conn retorna a transação ativa da sessão ou do contexto e, se não houver, a conexão do banco
+func (s *Session) conn(ctx context.Context) dbConn {
+	if active := s.active(ctx); active.tx != nil {
+		return active.tx
+	}
+	return s.db
+}
+
 // AutoMigrate executa migrações automáticas
 func (s *Session) AutoMigrate(ctx context.Context, models ...interface{}) error {
+	for _, model := range models {
+		if err := s.registerRelations(model); err != nil {
+			return err
+		}
+	}
+	
 	return s.migrator.AutoMigrate(ctx, models...)
 }
 
-// Transaction executa uma função dentro de uma transação
+// Migrator retorna o migrator da sessão, usado nas migrações versionadas
+func (s *Session) Migrator() *schema.Migrator {
+	return s.migrator
+}
+
+// Transaction executa uma função dentro de uma transação com as opções padrão da sessão.
+// Em uma sessão que já está em transação, usa um savepoint: um erro desfaz apenas o escopo interno.
+// A transação é colocada no contexto (veja Context) e uma transação já presente em ctx é reaproveitada.
 func (s *Session) Transaction(ctx context.Context, fn func(tx *Session) error) error {
-	return s.txManager.RunInTransaction(ctx, func(sqlTx *sql.Tx) error {
-		// Cria uma nova sessão com a transação
-		txSession := &Session{
-			db:        s.db,
-			dialect:   s.dialect,
-			tx:        sqlTx,
-			migrator:  s.migrator,
-			txManager: s.txManager,
-			cache:     s.cache,
-			hooks:     s.hooks,
-			logger:    s.logger,
-			validator: s.validator,
-			relations: s.relations,
-			metrics:   s.metrics,
-		}
-		
-		return fn(txSession)
+	return s.TransactionWith(ctx, s.txOptions, fn)
+}
+
+// TransactionWith executa uma função dentro de uma transação com as opções informadas.
+// Savepoints herdam as opções da transação externa e ignoram opts.
+func (s *Session) TransactionWith(ctx context.Context, opts transaction.Options, fn func(tx *Session) error) error {
+	if active := s.active(ctx); active != s {
+		return active.TransactionWith(ctx, opts, fn)
+	}
+	
+	if s.tx != nil {
+		return s.txManager.RunNested(ctx, s.tx, func(sqlTx *sql.Tx) error {
+			return fn(s.withTx(ctx, sqlTx, s.txOptions))
+		})
+	}
+	
+	s.logger.Debug(ctx, "Iniciando transação: %s", opts)
+	return s.txManager.RunWithOptions(ctx, opts, func(sqlTx *sql.Tx) error {
+		return fn(s.withTx(ctx, sqlTx, opts))
 	})
 }
 
+// OnCommit registra fn para ser executada após o commit da transação da sessão,
+// na ordem de registro. Fora de transação fn é executada imediatamente.
+// Erros de fn são registrados no log e não alteram o resultado da transação.
+func (s *Session) OnCommit(fn transaction.Callback) {
+	if s.tx == nil {
+		if err := fn(s.Context()); err != nil {
+			s.logger.Error(s.Context(), "Erro em callback após commit: %v", err)
+		}
+		return
+	}
+	s.txManager.OnCommit(s.tx, fn)
+}
+
+// OnRollback registra fn para ser executada se a transação (ou o savepoint) da sessão
+// for desfeita. Fora de transação fn nunca é executada.
+func (s *Session) OnRollback(fn transaction.Callback) {
+	if s.tx == nil {
+		return
+	}
+	s.txManager.OnRollback(s.tx, fn)
+}
+
+// SetRetryPolicy define a política de repetição de transações com falhas transitórias
+func (s *Session) SetRetryPolicy(policy transaction.RetryPolicy) {
+	s.txManager.SetRetryPolicy(policy)
+}
+
+// SetTxOptions define as opções padrão das transações da sessão
+func (s *Session) SetTxOptions(opts transaction.Options) {
+	s.txOptions = opts
+}
+
+// TxOptions retorna as opções da transação ativa, ou as padrão fora de transação
+func (s *Session) TxOptions() transaction.Options {
+	return s.txOptions
+}
+
+// TxDepth retorna o nível de aninhamento da transação da sessão (0 fora de transação)
+func (s *Session) TxDepth() int {
+	if s.tx == nil {
+		return 0
+	}
+	return s.txManager.Depth(s.tx)
+}
+
+// withTx cria uma nova sessão com a transação e o contexto que a carrega
+func (s *Session) withTx(ctx context.Context, sqlTx *sql.Tx, opts transaction.Options) *Session {
+	tx := &Session{
+		db:        s.db,
+		dialect:   s.dialect,
+		tx:        sqlTx,
+		migrator:  s.migrator,
+		txManager: s.txManager,
+		cache:     s.cache,
+		hooks:     s.hooks,
+		logger:    s.logger,
+		validator: s.validator,
+		relations: s.relations,
+		metrics:   s.metrics,
+		clock:     s.clock,
+		txOptions: opts,
+	}
+	tx.ctx = ContextWithTx(ctx, tx)
+	return tx
+}
+
 // Model cria um novo model handler para uma struct específica
 func (s *Session) Model(model interface{}) *ModelHandler {
-	return NewModelHandler(s, model)
+	// O mesmo mapeamento registra os relacionamentos e alimenta o manipulador
+	mapping, err := schema.NewParser().Parse(model)
+	if err == nil {
+		if err := s.relations.RegisterMapping(model, mapping); err != nil {
+			s.logger.Error(context.Background(), "Erro ao registrar relacionamentos: %v", err)
+		}
+	}
+	return newModelHandler(s, model, mapping, err)
+}
+
+// registerRelations registra os relacionamentos declarados pela tag rel do modelo
+func (s *Session) registerRelations(model interface{}) error {
+	mapping, err := schema.NewParser().Parse(model)
+	if err != nil {
+		return err
+	}
+	return s.relations.RegisterMapping(model, mapping)
 }
 
 // RegisterHook registra um novo hook
@@ -110,6 +236,8 @@ func (s *Session) Cache() *cache.Cache {
 // SetLogger define o logger
 func (s *Session) SetLogger(logger logger.Logger) {
 	s.logger = logger
+	s.txManager.SetLogger(logger)
+	s.migrator.SetLogger(logger)
 }
 
 // Logger retorna o logger
@@ -122,19 +250,24 @@ func (s *Session) Validator() *validator.Validator {
 	return s.validator
 }
 
-// HasOne define um relacionamento um-para-um
-func (s *Session) HasOne(model interface{}, field string, related interface{}, foreignKey string) {
-	s.relations.HasOne(model, field, related, foreignKey)
+// HasOne define um relacionamento um-para-um com a chave estrangeira no modelo relacionado
+func (s *Session) HasOne(model interface{}, field string, related interface{}, foreignKey string, opts ...relation.Option) error {
+	return s.relations.HasOne(model, field, related, foreignKey, opts...)
 }
 
 // HasMany define um relacionamento um-para-muitos
-func (s *Session) HasMany(model interface{}, field string, related interface{}, foreignKey string) {
-	s.relations.HasMany(model, field, related, foreignKey)
+func (s *Session) HasMany(model interface{}, field string, related interface{}, foreignKey string, opts ...relation.Option) error {
+	return s.relations.HasMany(model, field, related, foreignKey, opts...)
+}
+
+// BelongsTo define um relacionamento com a chave estrangeira no próprio modelo
+func (s *Session) BelongsTo(model interface{}, field string, related interface{}, foreignKey string, opts ...relation.Option) error {
+	return s.relations.BelongsTo(model, field, related, foreignKey, opts...)
 }
 
 // ManyToMany define um relacionamento muitos-para-muitos
-func (s *Session) ManyToMany(model interface{}, field string, related interface{}, joinTable string) {
-	s.relations.ManyToMany(model, field, related, joinTable)
+func (s *Session) ManyToMany(model interface{}, field string, related interface{}, joinTable string, opts ...relation.Option) error {
+	return s.relations.ManyToMany(model, field, related, joinTable, opts...)
 }
 
 // EnablePreload habilita o carregamento automático de um relacionamento
@@ -142,8 +275,17 @@ func (s *Session) EnablePreload(model interface{}, field string) {
 	s.relations.EnablePreload(model, field)
 }
 
+// SetClock define o relógio usado nos timestamps automáticos
+func (s *Session) SetClock(clock timestamp.Clock) {
+	s.clock = clock
+}
+
+// Clock retorna o relógio da sessão
+func (s *Session) Clock() timestamp.Clock {
+	return s.clock
+}
+
 // Metrics retorna o coletor de métricas
 func (s *Session) Metrics() *metrics.Collector {
 	return s.metrics
 }
- 
\ No newline at end of file
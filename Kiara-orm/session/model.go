@@ -2,8 +2,10 @@ package session
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"reflect"
+	"sort"
 	"time"
 	
 	"github.com/Flavio-coutinho/kiara-orm/query"
@@ -16,6 +18,9 @@ import (
 	"github.com/Flavio-coutinho/kiara-orm/scope"
 	"github.com/Flavio-coutinho/kiara-orm/pagination"
 	"github.com/Flavio-coutinho/kiara-orm/metrics"
+	"github.com/Flavio-coutinho/kiara-orm/timestamp"
+	"github.com/Flavio-coutinho/kiara-orm/types"
+	"github.com/Flavio-coutinho/kiara-orm/versioning"
 )
 
 // ModelHandler manipula operações em um modelo específico
@@ -26,24 +31,42 @@ type ModelHandler struct {
 	includeTrashed bool
 	onlyTrashed bool
 	preloadFields []string
+	preloadOptions map[string]PreloadOptions
+	joinFields []string
+	relationFilters []relationFilter
+	relationCounts []relationCount
 	scopes    []scope.Scope
 	paginator *pagination.Paginator
+	err       error // Erro ao analisar o modelo, retornado pelas operações
 }
 
-// NewModelHandler cria um novo manipulador de modelo
+// NewModelHandler cria um novo manipulador de modelo. Se o modelo não puder ser
+// analisado, as operações do manipulador retornam o erro.
 func NewModelHandler(session *Session, model interface{}) *ModelHandler {
-	parser := schema.NewParser()
-	mapping, _ := parser.Parse(model)
+	mapping, err := schema.NewParser().Parse(model)
+	return newModelHandler(session, model, mapping, err)
+}
+
+// newModelHandler cria o manipulador a partir de um mapeamento já analisado
+func newModelHandler(session *Session, model interface{}, mapping *schema.TableMapping, err error) *ModelHandler {
+	if err != nil {
+		err = fmt.Errorf("erro ao analisar modelo %T: %v", model, err)
+	}
 	
 	return &ModelHandler{
 		session: session,
 		model:   model,
 		mapping: mapping,
+		err:     err,
 	}
 }
 
 // Create insere um novo registro
 func (m *ModelHandler) Create(ctx context.Context, data interface{}) error {
+	if m.err != nil {
+		return m.err
+	}
+	
 	// Log da operação
 	m.session.logger.Debug(ctx, "Iniciando criação de registro em %s", m.mapping.TableName)
 	
@@ -58,15 +81,16 @@ func (m *ModelHandler) Create(ctx context.Context, data interface{}) error {
 		return err
 	}
 	
-	builder := m.session.Query().
-		Table(m.mapping.TableName)
-	
 	// Extrai valores dos campos
 	v := reflect.ValueOf(data)
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
 	
+	// Preenche created_at/updated_at
+	timestamp.SetCreateTime(m.mapping, v, m.session.clock())
+	versioning.Init(m.mapping, v)
+	
 	columns := make([]string, 0)
 	values := make([]interface{}, 0)
 	
@@ -76,7 +100,7 @@ func (m *ModelHandler) Create(ctx context.Context, data interface{}) error {
 		}
 		
 		columns = append(columns, field.Name)
-		values = append(values, v.FieldByName(field.Name).Interface())
+		values = append(values, v.FieldByName(field.FieldName).Interface())
 	}
 	
 	// Constrói e executa a query de inserção
@@ -87,15 +111,21 @@ func (m *ModelHandler) Create(ctx context.Context, data interface{}) error {
 		m.buildPlaceholders(len(columns)),
 	)
 	
-	_, err := m.session.db.ExecContext(ctx, query, values...)
+	result, err := m.session.conn(ctx).ExecContext(ctx, query, values...)
 	if err != nil {
 		return err
 	}
 	
+	// Preenche a chave primária auto incremento
+	if pk, ok := m.primaryKey(); ok && pk.IsAutoInc {
+		m.setInsertID(v, pk, result)
+	}
+	
 	// Executar hooks após a criação
 	if err := m.session.hooks.Execute(ctx, hooks.AfterCreate, data); err != nil {
 		return err
 	}
+	m.afterCommit(ctx, hooks.AfterCreateCommit, data)
 	
 	// Invalidar cache relacionado
 	cacheKey := fmt.Sprintf("table:%s", m.mapping.TableName)
@@ -106,10 +136,19 @@ func (m *ModelHandler) Create(ctx context.Context, data interface{}) error {
 
 // Find busca registros
 func (m *ModelHandler) Find(ctx context.Context, dest interface{}, conditions ...query.Condition) error {
+	if m.err != nil {
+		return m.err
+	}
+	
 	start := time.Now()
 	
 	builder := m.session.Query().Table(m.mapping.TableName)
 	
+	// Aplica relacionamentos carregados via JOIN
+	if err := m.applyJoins(builder); err != nil {
+		return err
+	}
+	
 	// Aplica scopes
 	for _, scope := range m.scopes {
 		builder = scope(ctx, builder)
@@ -117,7 +156,12 @@ func (m *ModelHandler) Find(ctx context.Context, dest interface{}, conditions ..
 	
 	// Aplica condições
 	for _, cond := range conditions {
-		builder.Where(cond.Column, cond.Operation, cond.Value)
+		builder.Where(m.qualify(cond.Column), cond.Operation, cond.Value)
+	}
+	
+	// Aplica filtros de existência de relacionamentos
+	if err := m.applyRelationFilters(builder); err != nil {
+		return err
 	}
 	
 	// Aplica paginação
@@ -128,7 +172,7 @@ func (m *ModelHandler) Find(ctx context.Context, dest interface{}, conditions ..
 			Table(m.mapping.TableName).
 			Select("COUNT(*) as count")
 		
-		err := m.session.Exec(countBuilder).QueryRow(ctx, &count)
+		err := m.session.ExecContext(ctx, countBuilder).QueryRow(ctx, &count)
 		if err != nil {
 			return err
 		}
@@ -140,7 +184,15 @@ func (m *ModelHandler) Find(ctx context.Context, dest interface{}, conditions ..
 			Offset(m.paginator.Offset())
 	}
 	
-	err := m.session.Exec(builder).Query(ctx, dest)
+	err := m.session.ExecContext(ctx, builder).Query(ctx, dest)
+	
+	// Carrega os relacionamentos e contagens solicitados
+	if err == nil {
+		err = m.loadRelations(ctx, dest)
+	}
+	if err == nil {
+		err = m.loadCounts(ctx, dest)
+	}
 	
 	// Registra métricas
 	duration := time.Since(start).Seconds()
@@ -160,25 +212,50 @@ func (m *ModelHandler) Find(ctx context.Context, dest interface{}, conditions ..
 	return err
 }
 
-// Update atualiza registros
+// Update atualiza registros. Se o modelo possui coluna de versão, a atualização
+// só é aplicada ao próprio registro, pela chave primária, se a versão não mudou;
+// caso contrário retorna um *versioning.StaleObjectError.
 func (m *ModelHandler) Update(ctx context.Context, data interface{}, conditions ...query.Condition) error {
+	if m.err != nil {
+		return m.err
+	}
+	
 	v := reflect.ValueOf(data)
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
 	
+	// Mapas (SoftDelete, Restore) atualizam apenas as colunas informadas
+	if v.Kind() == reflect.Map {
+		return m.updateColumns(ctx, v, conditions)
+	}
+	
+	// Atualiza updated_at
+	timestamp.SetUpdateTime(m.mapping, v, m.session.clock())
+	
+	versionField, hasVersion := versioning.Field(m.mapping)
+	var version int64
+	if hasVersion {
+		version = versioning.Get(v, versionField)
+	}
+	
 	// Constrói o SET da query
 	updates := make([]string, 0)
 	values := make([]interface{}, 0)
 	
 	for _, field := range m.mapping.Fields {
-		if field.IsPrimaryKey || field.IsAutoInc {
+		if field.IsPrimaryKey || field.IsAutoInc || field.AutoCreateTime != types.AutoTimeNone {
 			continue
 		}
 		
 		updates = append(updates, 
 			fmt.Sprintf("%s = ?", m.session.dialect.Quote(field.Name)))
-		values = append(values, v.FieldByName(field.Name).Interface())
+		
+		if field.IsVersion {
+			values = append(values, version+1)
+			continue
+		}
+		values = append(values, v.FieldByName(field.FieldName).Interface())
 	}
 	
 	// Adiciona condições WHERE
@@ -191,6 +268,21 @@ func (m *ModelHandler) Update(ctx context.Context, data interface{}, conditions
 		values = append(values, cond.Value)
 	}
 	
+	if hasVersion {
+		// A versão só identifica o registro junto com a chave primária
+		pk, err := m.versionedKey(v, conditions)
+		if err != nil {
+			return err
+		}
+		if pk != nil {
+			where = append(where, fmt.Sprintf("%s = ?", m.session.dialect.Quote(pk.Column)))
+			values = append(values, pk.Value)
+		}
+		
+		where = append(where, fmt.Sprintf("%s = ?", m.session.dialect.Quote(versionField.Name)))
+		values = append(values, version)
+	}
+	
 	// Constrói e executa a query
 	query := fmt.Sprintf(
 		"UPDATE %s SET %s WHERE %s",
@@ -199,12 +291,107 @@ func (m *ModelHandler) Update(ctx context.Context, data interface{}, conditions
 		m.joinWithAnd(where),
 	)
 	
-	_, err := m.session.db.ExecContext(ctx, query, values...)
-	return err
+	result, err := m.session.conn(ctx).ExecContext(ctx, query, values...)
+	if err != nil {
+		return err
+	}
+	
+	if hasVersion {
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		
+		if affected == 0 {
+			return &versioning.StaleObjectError{Table: m.mapping.TableName, Version: version}
+		}
+		
+		versioning.Set(v, versionField, version+1)
+	}
+	
+	m.afterCommit(ctx, hooks.AfterUpdateCommit, data)
+	return nil
+}
+
+// updateColumns atualiza as colunas de um map[string]interface{} e os campos
+// autoUpdateTime, sem verificar a versão do registro
+func (m *ModelHandler) updateColumns(ctx context.Context, columns reflect.Value, conditions []query.Condition) error {
+	updates := make([]string, 0)
+	values := make([]interface{}, 0)
+	
+	keys := make([]string, 0, columns.Len())
+	for _, key := range columns.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	
+	for _, key := range keys {
+		updates = append(updates, fmt.Sprintf("%s = ?", m.session.dialect.Quote(key)))
+		values = append(values, columns.MapIndex(reflect.ValueOf(key)).Interface())
+	}
+	
+	now := m.session.clock()
+	for _, field := range m.mapping.Fields {
+		if field.AutoUpdateTime == types.AutoTimeNone || columns.MapIndex(reflect.ValueOf(field.Name)).IsValid() {
+			continue
+		}
+		updates = append(updates, fmt.Sprintf("%s = ?", m.session.dialect.Quote(field.Name)))
+		values = append(values, timestamp.Value(field.AutoUpdateTime, now))
+	}
+	
+	where := make([]string, 0)
+	for _, cond := range conditions {
+		where = append(where,
+			fmt.Sprintf("%s %s ?", 
+				m.session.dialect.Quote(cond.Column),
+				cond.Operation))
+		values = append(values, cond.Value)
+	}
+	
+	query := fmt.Sprintf(
+		"UPDATE %s SET %s WHERE %s",
+		m.session.dialect.Quote(m.mapping.TableName),
+		m.joinWithComma(updates),
+		m.joinWithAnd(where),
+	)
+	
+	if _, err := m.session.conn(ctx).ExecContext(ctx, query, values...); err != nil {
+		return err
+	}
+	
+	m.afterCommit(ctx, hooks.AfterUpdateCommit, columns.Interface())
+	return nil
+}
+
+// Save atualiza o registro pela chave primária, ou o cria se a chave estiver vazia
+func (m *ModelHandler) Save(ctx context.Context, data interface{}) error {
+	if m.err != nil {
+		return m.err
+	}
+	
+	pk, ok := m.primaryKey()
+	if !ok {
+		return fmt.Errorf("modelo %s não possui chave primária", m.mapping.TableName)
+	}
+	
+	value := reflect.Indirect(reflect.ValueOf(data)).FieldByName(pk.FieldName)
+	if value.IsZero() {
+		return m.Create(ctx, data)
+	}
+	
+	return m.Update(ctx, data, query.Condition{
+		Column:    pk.Name,
+		Operation: query.OpEq,
+		Value:     value.Interface(),
+	})
 }
 
 // Delete remove registros
 func (m *ModelHandler) Delete(ctx context.Context, conditions ...query.Condition) error {
+	if m.err != nil {
+		return m.err
+	}
+	
 	where := make([]string, 0)
 	values := make([]interface{}, 0)
 	
@@ -222,11 +409,86 @@ func (m *ModelHandler) Delete(ctx context.Context, conditions ...query.Condition
 		m.joinWithAnd(where),
 	)
 	
-	_, err := m.session.db.ExecContext(ctx, query, values...)
-	return err
+	if _, err := m.session.conn(ctx).ExecContext(ctx, query, values...); err != nil {
+		return err
+	}
+	
+	m.afterCommit(ctx, hooks.AfterDeleteCommit, m.model)
+	return nil
+}
+
+// afterCommit agenda os hooks executados após o commit da transação ativa.
+// Fora de transação os hooks são executados imediatamente.
+func (m *ModelHandler) afterCommit(ctx context.Context, hookType hooks.HookType, value interface{}) {
+	if !m.session.hooks.HasHooks(hookType) {
+		return
+	}
+	
+	run := func(ctx context.Context) error {
+		return m.session.hooks.Execute(ctx, hookType, value)
+	}
+	
+	if active := m.session.active(ctx); active.tx != nil {
+		active.OnCommit(run)
+		return
+	}
+	
+	if err := run(ctx); err != nil {
+		m.session.logger.Error(ctx, "Erro em hook após commit: %v", err)
+	}
 }
 
 // Funções auxiliares
+func (m *ModelHandler) primaryKey() (types.FieldMapping, bool) {
+	for _, field := range m.mapping.Fields {
+		if field.IsPrimaryKey {
+			return field, true
+		}
+	}
+	return types.FieldMapping{}, false
+}
+
+// versionedKey retorna a condição pela chave primária de uma atualização
+// versionada, ou nil se as condições já filtram pela chave
+func (m *ModelHandler) versionedKey(v reflect.Value, conditions []query.Condition) (*query.Condition, error) {
+	pk, ok := m.primaryKey()
+	if !ok {
+		return nil, fmt.Errorf("modelo %s possui versão mas não possui chave primária", m.mapping.TableName)
+	}
+	
+	for _, cond := range conditions {
+		if cond.Column == pk.Name && cond.Operation == query.OpEq {
+			return nil, nil
+		}
+	}
+	
+	value := v.FieldByName(pk.FieldName)
+	if value.IsZero() {
+		return nil, fmt.Errorf("atualização versionada de %s exige a chave primária", m.mapping.TableName)
+	}
+	return &query.Condition{Column: pk.Name, Operation: query.OpEq, Value: value.Interface()}, nil
+}
+
+// setInsertID copia o ID gerado pelo banco para o registro, quando suportado pelo driver
+func (m *ModelHandler) setInsertID(v reflect.Value, pk types.FieldMapping, result sql.Result) {
+	field := v.FieldByName(pk.FieldName)
+	if !field.CanSet() || !field.IsZero() {
+		return
+	}
+	
+	id, err := result.LastInsertId()
+	if err != nil {
+		return
+	}
+	
+	switch field.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		field.SetInt(id)
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		field.SetUint(uint64(id))
+	}
+}
+
 func (m *ModelHandler) buildColumnList(columns []string) string {
 	quoted := make([]string, len(columns))
 	for i, col := range columns {
@@ -294,10 +556,10 @@ func (m *ModelHandler) copyFromCache(cached, dest interface{}) error {
 
 // SoftDelete realiza uma exclusão lógica
 func (m *ModelHandler) SoftDelete(ctx context.Context, conditions ...query.Condition) error {
-	now := time.Now()
+	now := m.session.clock()
 	
 	updates := map[string]interface{}{
-		"deleted_at": &now,
+		softdelete.Column: &now,
 	}
 	
 	return m.Update(ctx, updates, conditions...)
@@ -306,7 +568,7 @@ func (m *ModelHandler) SoftDelete(ctx context.Context, conditions ...query.Condi
 // Restore restaura registros excluídos logicamente
 func (m *ModelHandler) Restore(ctx context.Context, conditions ...query.Condition) error {
 	updates := map[string]interface{}{
-		"deleted_at": nil,
+		softdelete.Column: nil,
 	}
 	
 	return m.Update(ctx, updates, conditions...)
@@ -326,53 +588,62 @@ func (m *ModelHandler) OnlyTrashed() *ModelHandler {
 
 // BulkCreate insere múltiplos registros
 func (m *ModelHandler) BulkCreate(ctx context.Context, records []interface{}) error {
-	bulkOp := bulk.NewBulkOperation(m.session.dialect, m.mapping, 1000)
-	return bulkOp.BulkInsert(ctx, m.session.db, records)
+	if m.err != nil {
+		return m.err
+	}
+	
+	bulkOp := bulk.NewBulkOperation(m.session.dialect, m.mapping, 1000).
+		WithClock(m.session.clock)
+	return bulkOp.BulkInsert(ctx, m.session.conn(ctx), records)
 }
 
-// BulkUpdate atualiza múltiplos registros
+// BulkUpdate atualiza múltiplos registros em uma transação (ou savepoint, se já
+// houver uma ativa): um conflito de versão desfaz todas as atualizações da chamada
 func (m *ModelHandler) BulkUpdate(ctx context.Context, records []interface{}, conditions map[string]interface{}) error {
-	bulkOp := bulk.NewBulkOperation(m.session.dialect, m.mapping, 1000)
-	return bulkOp.BulkUpdate(ctx, m.session.db, records, conditions)
+	if m.err != nil {
+		return m.err
+	}
+	
+	bulkOp := bulk.NewBulkOperation(m.session.dialect, m.mapping, 1000).
+		WithClock(m.session.clock)
+	return m.session.Transaction(ctx, func(tx *Session) error {
+		return bulkOp.BulkUpdate(tx.Context(), tx.tx, records, conditions)
+	})
 }
 
 // BulkDelete deleta múltiplos registros
 func (m *ModelHandler) BulkDelete(ctx context.Context, ids []interface{}) error {
+	if m.err != nil {
+		return m.err
+	}
+	
 	bulkOp := bulk.NewBulkOperation(m.session.dialect, m.mapping, 1000)
-	return bulkOp.BulkDelete(ctx, m.session.db, ids)
+	return bulkOp.BulkDelete(ctx, m.session.conn(ctx), ids)
 }
 
-// Preload carrega relacionamentos
+// Preload carrega relacionamentos. Aceita caminhos aninhados como "Posts.Comments.Author"
 func (m *ModelHandler) Preload(fields ...string) *ModelHandler {
 	m.preloadFields = append(m.preloadFields, fields...)
 	return m
 }
 
-// loadRelations carrega os relacionamentos solicitados
-func (m *ModelHandler) loadRelations(ctx context.Context, dest interface{}) error {
-	for _, field := range m.preloadFields {
-		if relation, ok := m.session.relations.GetRelation(m.model, field); ok {
-			if err := m.loadRelation(ctx, dest, field, relation); err != nil {
-				return err
-			}
-		}
+// PreloadWith carrega um relacionamento aplicando condições e ordenação à
+// consulta relacionada; o limite é aplicado em memória, por registro pai. Em
+// caminhos aninhados as opções valem para o último nível.
+func (m *ModelHandler) PreloadWith(path string, options PreloadOptions) *ModelHandler {
+	if m.preloadOptions == nil {
+		m.preloadOptions = make(map[string]PreloadOptions)
 	}
-	return nil
+	m.preloadOptions[path] = options
+	m.preloadFields = append(m.preloadFields, path)
+	return m
 }
 
-// loadRelation carrega um relacionamento específico
-func (m *ModelHandler) loadRelation(ctx context.Context, dest interface{}, field string, relation Relation) error {
-	// Implementa a lógica de carregamento baseada no tipo de relacionamento
-	switch relation.Type {
-	case relation.OneToOne:
-		return m.loadOneToOne(ctx, dest, field, relation)
-	case relation.OneToMany:
-		return m.loadOneToMany(ctx, dest, field, relation)
-	case relation.ManyToMany:
-		return m.loadManyToMany(ctx, dest, field, relation)
-	default:
-		return fmt.Errorf("tipo de relacionamento não suportado")
-	}
+// Joins carrega relacionamentos BelongsTo/HasOne na mesma consulta, com LEFT JOIN.
+// Relacionamentos inexistentes ficam nil.
+func (m *ModelHandler) Joins(fields ...string) *ModelHandler {
+	m.joinFields = append(m.joinFields, fields...)
+	return m
 }
 
 // Scope adiciona um scope à query
@@ -0,0 +1,577 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	
+	"github.com/Flavio-coutinho/Kiara-orm/query"
+	"github.com/Flavio-coutinho/Kiara-orm/relation"
+	"github.com/Flavio-coutinho/Kiara-orm/schema"
+	"github.com/Flavio-coutinho/Kiara-orm/scope"
+	"github.com/Flavio-coutinho/Kiara-orm/softdelete"
+)
+
+// PreloadOptions configura a consulta de um relacionamento pré-carregado.
+// Limit não limita a consulta: todos os relacionados são buscados e cada
+// registro pai recebe, em memória, apenas os primeiros.
+type PreloadOptions struct {
+	Conditions  []query.Condition
+	Scopes      []scope.Scope
+	OrderBy     string
+	Desc        bool
+	Limit       int  // Corte em memória por registro pai (0 = sem limite)
+	WithTrashed bool // Inclui registros excluídos logicamente
+}
+
+// preloadNode representa um nível de um caminho de preload (ex.: Posts -> Comments)
+type preloadNode struct {
+	field    string
+	options  PreloadOptions
+	children []*preloadNode
+}
+
+// loadRelations carrega os relacionamentos solicitados via Preload e os
+// habilitados com EnablePreload. Cada relacionamento gera uma única consulta
+// com IN (...) para todos os registros de dest, evitando o problema N+1.
+func (m *ModelHandler) loadRelations(ctx context.Context, dest interface{}) error {
+	fields := append(m.session.relations.GetPreloadFields(m.model), m.preloadFields...)
+	if len(fields) == 0 {
+		return nil
+	}
+	
+	records := collectRecords(dest)
+	if len(records) == 0 {
+		return nil
+	}
+	
+	return m.loadNodes(ctx, records, m.preloadTree(fields))
+}
+
+// preloadTree agrupa os caminhos de preload em uma árvore, para que cada
+// relacionamento intermediário seja carregado uma única vez
+func (m *ModelHandler) preloadTree(paths []string) []*preloadNode {
+	root := &preloadNode{}
+	
+	for _, path := range paths {
+		node := root
+		segments := strings.Split(path, ".")
+		
+		for i, segment := range segments {
+			var child *preloadNode
+			for _, existing := range node.children {
+				if existing.field == segment {
+					child = existing
+					break
+				}
+			}
+			
+			if child == nil {
+				child = &preloadNode{field: segment}
+				node.children = append(node.children, child)
+			}
+			
+			if options, ok := m.preloadOptions[strings.Join(segments[:i+1], ".")]; ok {
+				child.options = options
+			}
+			node = child
+		}
+	}
+	
+	return root.children
+}
+
+// loadNodes carrega cada relacionamento do nível atual e desce para os níveis aninhados
+func (m *ModelHandler) loadNodes(ctx context.Context, records []reflect.Value, nodes []*preloadNode) error {
+	model := reflect.New(records[0].Type()).Interface()
+	
+	// Modelos aninhados podem ainda não ter tido suas tags rel registradas
+	if err := m.session.registerRelations(model); err != nil {
+		return err
+	}
+	
+	for _, node := range nodes {
+		rel, ok := m.session.relations.GetRelation(model, node.field)
+		if !ok {
+			return fmt.Errorf("relacionamento %s não definido para %s", node.field, records[0].Type().Name())
+		}
+		
+		if err := m.loadRelation(ctx, records, node.field, rel, node.options); err != nil {
+			return fmt.Errorf("erro ao carregar %s: %w", node.field, err)
+		}
+		
+		if len(node.children) == 0 {
+			continue
+		}
+		
+		if nested := collectField(records, node.field); len(nested) > 0 {
+			if err := m.loadNodes(ctx, nested, node.children); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyJoins seleciona as colunas do modelo e dos relacionamentos pedidos em Joins.
+// As colunas relacionadas recebem o apelido Campo__coluna, lido pelo query.Executor.
+func (m *ModelHandler) applyJoins(builder *query.Builder) error {
+	if len(m.joinFields) == 0 {
+		return nil
+	}
+	
+	table := m.mapping.TableName
+	for _, field := range m.mapping.Fields {
+		builder.SelectAs(table+"."+field.Name, field.Name)
+	}
+	
+	d := m.session.dialect
+	for _, name := range m.joinFields {
+		rel, ok := m.session.relations.GetRelation(m.model, name)
+		if !ok {
+			return fmt.Errorf("relacionamento %s não definido para %s", name, table)
+		}
+		
+		if rel.Type != relation.BelongsTo && rel.Type != relation.OneToOne {
+			return fmt.Errorf("Joins suporta apenas BelongsTo e HasOne, use Preload para %s", name)
+		}
+		if rel.IsPolymorphic() {
+			return fmt.Errorf("Joins não suporta relacionamentos polimórficos, use Preload para %s", name)
+		}
+		
+		related, err := schema.NewParser().Parse(rel.Model)
+		if err != nil {
+			return err
+		}
+		
+		// Em BelongsTo a chave estrangeira está no modelo; em HasOne, no relacionado
+		relatedKey, ownerKey := rel.ReferenceKey, rel.ForeignKey
+		if rel.Type == relation.OneToOne {
+			relatedKey, ownerKey = rel.ForeignKey, rel.ReferenceKey
+		}
+		
+		relatedColumn, err := columnFor(related, relatedKey)
+		if err != nil {
+			return err
+		}
+		ownerColumn, err := columnFor(m.mapping, ownerKey)
+		if err != nil {
+			return err
+		}
+		
+		on := fmt.Sprintf("%s.%s = %s.%s", d.Quote(name), d.Quote(relatedColumn), d.Quote(table), d.Quote(ownerColumn))
+		if softdelete.HasColumn(related) {
+			on += fmt.Sprintf(" AND %s.%s IS NULL", d.Quote(name), d.Quote(softdelete.Column))
+		}
+		builder.JoinAs("LEFT", related.TableName, name, on)
+		
+		for _, field := range related.Fields {
+			builder.SelectAs(name+"."+field.Name, name+query.JoinSeparator+field.Name)
+		}
+	}
+	
+	return nil
+}
+
+// qualify prefixa a coluna com a tabela do modelo quando há JOINs, evitando ambiguidade
+func (m *ModelHandler) qualify(column string) string {
+	if len(m.joinFields) == 0 || strings.Contains(column, ".") {
+		return column
+	}
+	return m.mapping.TableName + "." + column
+}
+
+// loadRelation carrega um relacionamento específico
+func (m *ModelHandler) loadRelation(ctx context.Context, records []reflect.Value, field string, rel relation.Relation, options PreloadOptions) error {
+	// Implementa a lógica de carregamento baseada no tipo de relacionamento
+	switch rel.Type {
+	case relation.OneToOne:
+		return m.loadOneToOne(ctx, records, field, rel, options)
+	case relation.OneToMany:
+		return m.loadOneToMany(ctx, records, field, rel, options)
+	case relation.ManyToMany:
+		return m.loadManyToMany(ctx, records, field, rel, options)
+	case relation.BelongsTo:
+		return m.loadBelongsTo(ctx, records, field, rel, options)
+	default:
+		return fmt.Errorf("tipo de relacionamento não suportado")
+	}
+}
+
+// loadOneToOne preenche um campo ponteiro (ou struct) com o registro relacionado
+func (m *ModelHandler) loadOneToOne(ctx context.Context, records []reflect.Value, field string, rel relation.Relation, options PreloadOptions) error {
+	related, err := m.fetchByOwnerKey(ctx, records, rel, options)
+	if err != nil {
+		return err
+	}
+	
+	for _, record := range records {
+		matches := related[keyOf(record.FieldByName(rel.ReferenceKey).Interface())]
+		if len(matches) > 0 {
+			assignOne(record.FieldByName(field), matches[0])
+		}
+	}
+	return nil
+}
+
+// loadOneToMany preenche um campo slice com os registros relacionados
+func (m *ModelHandler) loadOneToMany(ctx context.Context, records []reflect.Value, field string, rel relation.Relation, options PreloadOptions) error {
+	related, err := m.fetchByOwnerKey(ctx, records, rel, options)
+	if err != nil {
+		return err
+	}
+	
+	for _, record := range records {
+		target := record.FieldByName(field)
+		target.Set(reflect.MakeSlice(target.Type(), 0, 0))
+		
+		for _, item := range related[keyOf(record.FieldByName(rel.ReferenceKey).Interface())] {
+			if options.Limit > 0 && target.Len() >= options.Limit {
+				break
+			}
+			appendMany(target, item)
+		}
+	}
+	return nil
+}
+
+// loadManyToMany preenche um campo slice através da tabela de junção.
+// São feitas duas consultas: uma na tabela de junção e outra na tabela relacionada.
+func (m *ModelHandler) loadManyToMany(ctx context.Context, records []reflect.Value, field string, rel relation.Relation, options PreloadOptions) error {
+	relatedMapping, err := schema.NewParser().Parse(rel.Model)
+	if err != nil {
+		return err
+	}
+	
+	ownerColumn, relatedColumn := rel.JoinForeignKey, rel.JoinReferenceKey
+	
+	keys := distinctKeys(records, rel.ReferenceKey)
+	if len(keys) == 0 {
+		return nil
+	}
+	
+	// Lê os pares da tabela de junção
+	joinBuilder := m.session.Query().
+		Table(rel.JoinTable).
+		Select(ownerColumn, relatedColumn).
+		Where(ownerColumn, query.OpIn, keys)
+	
+	rows, err := m.session.ExecContext(ctx, joinBuilder).Rows(ctx)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	
+	// owners mapeia a chave do registro relacionado para as chaves dos donos
+	owners := make(map[string][]string)
+	relatedKeys := make([]interface{}, 0)
+	
+	for rows.Next() {
+		var ownerKey, relatedKey interface{}
+		if err := rows.Scan(&ownerKey, &relatedKey); err != nil {
+			return err
+		}
+		
+		if _, seen := owners[keyOf(relatedKey)]; !seen {
+			relatedKeys = append(relatedKeys, relatedKey)
+		}
+		owners[keyOf(relatedKey)] = append(owners[keyOf(relatedKey)], keyOf(ownerKey))
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	
+	associationColumn, err := columnFor(relatedMapping, rel.AssociationKey)
+	if err != nil {
+		return err
+	}
+	
+	related, err := m.fetchRelated(ctx, rel, relatedMapping, associationColumn, relatedKeys, options)
+	if err != nil {
+		return err
+	}
+	
+	byKey := make(map[string][]reflect.Value)
+	for _, record := range records {
+		target := record.FieldByName(field)
+		target.Set(reflect.MakeSlice(target.Type(), 0, 0))
+		
+		key := keyOf(record.FieldByName(rel.ReferenceKey).Interface())
+		byKey[key] = append(byKey[key], record)
+	}
+	
+	// Percorre os relacionados na ordem da consulta para respeitar OrderBy e Limit
+	for i := 0; i < related.Len(); i++ {
+		item := related.Index(i)
+		for _, ownerKey := range owners[keyOf(item.Elem().FieldByName(rel.AssociationKey).Interface())] {
+			for _, record := range byKey[ownerKey] {
+				target := record.FieldByName(field)
+				if options.Limit > 0 && target.Len() >= options.Limit {
+					continue
+				}
+				appendMany(target, item)
+			}
+		}
+	}
+	return nil
+}
+
+// loadBelongsTo carrega o registro referenciado pela chave estrangeira do próprio modelo
+func (m *ModelHandler) loadBelongsTo(ctx context.Context, records []reflect.Value, field string, rel relation.Relation, options PreloadOptions) error {
+	relatedMapping, err := schema.NewParser().Parse(rel.Model)
+	if err != nil {
+		return err
+	}
+	
+	referenceColumn, err := columnFor(relatedMapping, rel.ReferenceKey)
+	if err != nil {
+		return err
+	}
+	
+	keys := distinctKeys(records, rel.ForeignKey)
+	if len(keys) == 0 {
+		return nil
+	}
+	
+	related, err := m.fetchRelated(ctx, rel, relatedMapping, referenceColumn, keys, options)
+	if err != nil {
+		return err
+	}
+	
+	byKey := indexBy(related, rel.ReferenceKey)
+	for _, record := range records {
+		matches := byKey[keyOf(record.FieldByName(rel.ForeignKey).Interface())]
+		if len(matches) > 0 {
+			assignOne(record.FieldByName(field), matches[0])
+		}
+	}
+	return nil
+}
+
+// fetchByOwnerKey busca os registros relacionados cuja chave estrangeira aponta
+// para os registros carregados, agrupados pelo valor da chave estrangeira
+func (m *ModelHandler) fetchByOwnerKey(ctx context.Context, records []reflect.Value, rel relation.Relation, options PreloadOptions) (map[string][]reflect.Value, error) {
+	relatedMapping, err := schema.NewParser().Parse(rel.Model)
+	if err != nil {
+		return nil, err
+	}
+	
+	foreignColumn, err := columnFor(relatedMapping, rel.ForeignKey)
+	if err != nil {
+		return nil, err
+	}
+	
+	keys := distinctKeys(records, rel.ReferenceKey)
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	
+	// Em relacionamentos polimórficos filtra também pelo tipo do dono
+	if rel.IsPolymorphic() {
+		typeColumn, err := columnFor(relatedMapping, rel.PolymorphicType)
+		if err != nil {
+			return nil, err
+		}
+		
+		conditions := []query.Condition{{Column: typeColumn, Operation: query.OpEq, Value: rel.PolymorphicValue}}
+		options.Conditions = append(conditions, options.Conditions...)
+	}
+	
+	related, err := m.fetchRelated(ctx, rel, relatedMapping, foreignColumn, keys, options)
+	if err != nil {
+		return nil, err
+	}
+	
+	return indexBy(related, rel.ForeignKey), nil
+}
+
+// fetchRelated executa a consulta em lote no modelo relacionado e retorna um slice de ponteiros.
+// Registros excluídos logicamente são ignorados, salvo com WithTrashed.
+func (m *ModelHandler) fetchRelated(ctx context.Context, rel relation.Relation, mapping *schema.TableMapping, column string, keys []interface{}, options PreloadOptions) (reflect.Value, error) {
+	relatedType := reflect.TypeOf(rel.Model)
+	if relatedType.Kind() == reflect.Ptr {
+		relatedType = relatedType.Elem()
+	}
+	
+	results := reflect.New(reflect.SliceOf(reflect.PtrTo(relatedType)))
+	if len(keys) == 0 {
+		return results.Elem(), nil
+	}
+	
+	builder := m.session.Query().
+		Table(mapping.TableName).
+		Where(column, query.OpIn, keys)
+	
+	if !options.WithTrashed && softdelete.HasColumn(mapping) {
+		builder.Where(softdelete.Column, query.OpIsNull, nil)
+	}
+	
+	for _, cond := range options.Conditions {
+		builder.Where(cond.Column, cond.Operation, cond.Value)
+	}
+	
+	for _, scope := range options.Scopes {
+		builder = scope(ctx, builder)
+	}
+	
+	if options.OrderBy != "" {
+		builder.OrderBy(options.OrderBy, options.Desc)
+	}
+	
+	if err := m.session.ExecContext(ctx, builder).Query(ctx, results.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	
+	return results.Elem(), nil
+}
+
+// Funções auxiliares de preload
+
+// collectRecords retorna as structs endereçáveis contidas em dest (struct ou slice)
+func collectRecords(dest interface{}) []reflect.Value {
+	v := reflect.Indirect(reflect.ValueOf(dest))
+	if v.Kind() == reflect.Struct {
+		return []reflect.Value{v}
+	}
+	
+	records := make([]reflect.Value, 0)
+	if v.Kind() != reflect.Slice {
+		return records
+	}
+	
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				continue
+			}
+			elem = elem.Elem()
+		}
+		records = append(records, elem)
+	}
+	return records
+}
+
+// collectField retorna as structs carregadas em um campo de relacionamento dos
+// registros. Um registro compartilhado por vários (ex.: o mesmo pai em BelongsTo)
+// aparece uma única vez, para não ser preenchido em duplicidade no nível seguinte.
+func collectField(records []reflect.Value, field string) []reflect.Value {
+	nested := make([]reflect.Value, 0)
+	seen := make(map[uintptr]bool)
+	add := func(item reflect.Value) {
+		if item.CanAddr() {
+			address := item.Addr().Pointer()
+			if seen[address] {
+				return
+			}
+			seen[address] = true
+		}
+		nested = append(nested, item)
+	}
+	
+	for _, record := range records {
+		value := record.FieldByName(field)
+		
+		switch value.Kind() {
+		case reflect.Ptr:
+			if !value.IsNil() {
+				add(value.Elem())
+			}
+		case reflect.Struct:
+			add(value)
+		case reflect.Slice:
+			for i := 0; i < value.Len(); i++ {
+				item := value.Index(i)
+				if item.Kind() == reflect.Ptr {
+					if item.IsNil() {
+						continue
+					}
+					item = item.Elem()
+				}
+				add(item)
+			}
+		}
+	}
+	return nested
+}
+
+// distinctKeys retorna os valores distintos e não nulos de um campo dos registros
+func distinctKeys(records []reflect.Value, fieldName string) []interface{} {
+	keys := make([]interface{}, 0, len(records))
+	seen := make(map[string]bool)
+	
+	for _, record := range records {
+		value := record.FieldByName(fieldName)
+		if !value.IsValid() || value.IsZero() {
+			continue
+		}
+		
+		key := keyOf(value.Interface())
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, reflect.Indirect(value).Interface())
+		}
+	}
+	return keys
+}
+
+// indexBy agrupa um slice de ponteiros pelo valor de um campo
+func indexBy(items reflect.Value, fieldName string) map[string][]reflect.Value {
+	index := make(map[string][]reflect.Value)
+	for i := 0; i < items.Len(); i++ {
+		item := items.Index(i)
+		key := keyOf(item.Elem().FieldByName(fieldName).Interface())
+		index[key] = append(index[key], item)
+	}
+	return index
+}
+
+// keyOf normaliza um valor de chave para comparação entre tipos distintos
+// (ex.: int na struct e int64 ou []byte vindos do driver)
+func keyOf(value interface{}) string {
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+	
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	
+	if !v.IsValid() {
+		return ""
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// assignOne atribui o registro relacionado (ponteiro) a um campo ponteiro ou struct
+func assignOne(field reflect.Value, item reflect.Value) {
+	if field.Kind() == reflect.Ptr {
+		field.Set(item)
+		return
+	}
+	field.Set(item.Elem())
+}
+
+// appendMany adiciona o registro relacionado (ponteiro) a um campo slice
+func appendMany(field reflect.Value, item reflect.Value) {
+	if field.Type().Elem().Kind() == reflect.Ptr {
+		field.Set(reflect.Append(field, item))
+		return
+	}
+	field.Set(reflect.Append(field, item.Elem()))
+}
+
+// columnFor retorna o nome da coluna de um campo da struct
+func columnFor(mapping *schema.TableMapping, fieldName string) (string, error) {
+	for _, field := range mapping.Fields {
+		if field.FieldName == fieldName {
+			return field.Name, nil
+		}
+	}
+	return "", fmt.Errorf("campo %s não encontrado em %s", fieldName, mapping.TableName)
+}
@@ -0,0 +1,278 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	
+	"github.com/Flavio-coutinho/Kiara-orm/query"
+	"github.com/Flavio-coutinho/Kiara-orm/relation"
+	"github.com/Flavio-coutinho/Kiara-orm/schema"
+	"github.com/Flavio-coutinho/Kiara-orm/softdelete"
+)
+
+// relationFilter representa um filtro WhereHas/WhereDoesntHave
+type relationFilter struct {
+	field      string
+	conditions []query.Condition
+	negate     bool
+}
+
+// relationCount representa uma contagem solicitada via WithCount
+type relationCount struct {
+	field string
+	dest  map[interface{}]int64 // nil preenche o campo <Relacionamento>Count
+}
+
+// WhereHas filtra os registros que possuem ao menos um relacionado que atenda às condições.
+// As condições se referem às colunas do modelo relacionado.
+func (m *ModelHandler) WhereHas(field string, conditions ...query.Condition) *ModelHandler {
+	m.relationFilters = append(m.relationFilters, relationFilter{field: field, conditions: conditions})
+	return m
+}
+
+// WhereDoesntHave filtra os registros que não possuem relacionados que atendam às condições
+func (m *ModelHandler) WhereDoesntHave(field string, conditions ...query.Condition) *ModelHandler {
+	m.relationFilters = append(m.relationFilters, relationFilter{field: field, conditions: conditions, negate: true})
+	return m
+}
+
+// WithCount conta os relacionados de cada registro carregado e preenche o campo
+// <Relacionamento>Count do modelo, por exemplo PostsCount int `db:"-"`
+func (m *ModelHandler) WithCount(field string) *ModelHandler {
+	m.relationCounts = append(m.relationCounts, relationCount{field: field})
+	return m
+}
+
+// WithCountMap conta os relacionados de cada registro carregado e grava o resultado
+// em dest, indexado pela chave primária do registro
+func (m *ModelHandler) WithCountMap(field string, dest map[interface{}]int64) *ModelHandler {
+	m.relationCounts = append(m.relationCounts, relationCount{field: field, dest: dest})
+	return m
+}
+
+// applyRelationFilters adiciona ao builder as subqueries EXISTS dos filtros de relacionamento
+func (m *ModelHandler) applyRelationFilters(builder *query.Builder) error {
+	for _, filter := range m.relationFilters {
+		sub, err := m.existsSubquery(filter)
+		if err != nil {
+			return err
+		}
+		
+		if filter.negate {
+			builder.WhereNotExists(sub)
+		} else {
+			builder.WhereExists(sub)
+		}
+	}
+	return nil
+}
+
+// existsSubquery monta a subquery correlacionada com a tabela do modelo
+func (m *ModelHandler) existsSubquery(filter relationFilter) (*query.Builder, error) {
+	rel, ok := m.session.relations.GetRelation(m.model, filter.field)
+	if !ok {
+		return nil, fmt.Errorf("relacionamento %s não definido para %s", filter.field, m.mapping.TableName)
+	}
+	
+	related, err := schema.NewParser().Parse(rel.Model)
+	if err != nil {
+		return nil, err
+	}
+	
+	table := m.mapping.TableName
+	sub := m.session.Query().Table(related.TableName).SelectRaw("1")
+	
+	switch rel.Type {
+	case relation.ManyToMany:
+		assocColumn, err := columnFor(related, rel.AssociationKey)
+		if err != nil {
+			return nil, err
+		}
+		ownerColumn, err := columnFor(m.mapping, rel.ReferenceKey)
+		if err != nil {
+			return nil, err
+		}
+		
+		d := m.session.dialect
+		sub.Join("INNER", rel.JoinTable, fmt.Sprintf("%s.%s = %s.%s",
+			d.Quote(rel.JoinTable), d.Quote(rel.JoinReferenceKey),
+			d.Quote(related.TableName), d.Quote(assocColumn)))
+		sub.WhereColumn(rel.JoinTable+"."+rel.JoinForeignKey, query.OpEq, table+"."+ownerColumn)
+	
+	case relation.BelongsTo:
+		refColumn, err := columnFor(related, rel.ReferenceKey)
+		if err != nil {
+			return nil, err
+		}
+		fkColumn, err := columnFor(m.mapping, rel.ForeignKey)
+		if err != nil {
+			return nil, err
+		}
+		sub.WhereColumn(related.TableName+"."+refColumn, query.OpEq, table+"."+fkColumn)
+	
+	default:
+		fkColumn, err := columnFor(related, rel.ForeignKey)
+		if err != nil {
+			return nil, err
+		}
+		ownerColumn, err := columnFor(m.mapping, rel.ReferenceKey)
+		if err != nil {
+			return nil, err
+		}
+		sub.WhereColumn(related.TableName+"."+fkColumn, query.OpEq, table+"."+ownerColumn)
+		
+		if rel.IsPolymorphic() {
+			typeColumn, err := columnFor(related, rel.PolymorphicType)
+			if err != nil {
+				return nil, err
+			}
+			sub.Where(related.TableName+"."+typeColumn, query.OpEq, rel.PolymorphicValue)
+		}
+	}
+	
+	if softdelete.HasColumn(related) {
+		sub.Where(related.TableName+"."+softdelete.Column, query.OpIsNull, nil)
+	}
+	
+	// Colunas sem tabela são qualificadas com a tabela relacionada
+	for _, cond := range filter.conditions {
+		column := cond.Column
+		if !strings.Contains(column, ".") {
+			column = related.TableName + "." + column
+		}
+		sub.Where(column, cond.Operation, cond.Value)
+	}
+	
+	return sub, nil
+}
+
+// loadCounts executa as contagens solicitadas com WithCount, uma consulta
+// agrupada por relacionamento para todos os registros de dest
+func (m *ModelHandler) loadCounts(ctx context.Context, dest interface{}) error {
+	if len(m.relationCounts) == 0 {
+		return nil
+	}
+	
+	records := collectRecords(dest)
+	if len(records) == 0 {
+		return nil
+	}
+	
+	for _, count := range m.relationCounts {
+		if err := m.loadCount(ctx, records, count); err != nil {
+			return fmt.Errorf("erro ao contar %s: %w", count.field, err)
+		}
+	}
+	return nil
+}
+
+// loadCount conta os relacionados de um relacionamento e atribui o resultado aos registros
+func (m *ModelHandler) loadCount(ctx context.Context, records []reflect.Value, count relationCount) error {
+	rel, ok := m.session.relations.GetRelation(m.model, count.field)
+	if !ok {
+		return fmt.Errorf("relacionamento %s não definido para %s", count.field, m.mapping.TableName)
+	}
+	
+	related, err := schema.NewParser().Parse(rel.Model)
+	if err != nil {
+		return err
+	}
+	
+	// ownerField é o campo do registro comparado com keyColumn na tabela contada
+	ownerField := rel.ReferenceKey
+	var builder *query.Builder
+	var keyColumn string
+	
+	switch rel.Type {
+	case relation.ManyToMany:
+		keyColumn = rel.JoinForeignKey
+		builder = m.session.Query().Table(rel.JoinTable)
+	
+	case relation.BelongsTo:
+		ownerField = rel.ForeignKey
+		if keyColumn, err = columnFor(related, rel.ReferenceKey); err != nil {
+			return err
+		}
+		builder = m.session.Query().Table(related.TableName)
+	
+	default:
+		if keyColumn, err = columnFor(related, rel.ForeignKey); err != nil {
+			return err
+		}
+		builder = m.session.Query().Table(related.TableName)
+		
+		if rel.IsPolymorphic() {
+			typeColumn, err := columnFor(related, rel.PolymorphicType)
+			if err != nil {
+				return err
+			}
+			builder.Where(typeColumn, query.OpEq, rel.PolymorphicValue)
+		}
+	}
+	
+	if rel.Type != relation.ManyToMany && softdelete.HasColumn(related) {
+		builder.Where(softdelete.Column, query.OpIsNull, nil)
+	}
+	
+	counts := make(map[string]int64)
+	if keys := distinctKeys(records, ownerField); len(keys) > 0 {
+		builder.Select(keyColumn).
+			SelectRaw("COUNT(*)").
+			Where(keyColumn, query.OpIn, keys).
+			GroupBy(keyColumn)
+		
+		rows, err := m.session.ExecContext(ctx, builder).Rows(ctx)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		
+		for rows.Next() {
+			var key interface{}
+			var total int64
+			if err := rows.Scan(&key, &total); err != nil {
+				return err
+			}
+			counts[keyOf(key)] = total
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+	
+	return m.assignCounts(records, count, ownerField, counts)
+}
+
+// assignCounts grava as contagens no mapa de destino ou no campo <Relacionamento>Count
+func (m *ModelHandler) assignCounts(records []reflect.Value, count relationCount, ownerField string, counts map[string]int64) error {
+	var pk string
+	if count.dest != nil {
+		field, ok := m.primaryKey()
+		if !ok {
+			return fmt.Errorf("tabela %s não possui chave primária", m.mapping.TableName)
+		}
+		pk = field.FieldName
+	}
+	
+	for _, record := range records {
+		total := counts[keyOf(record.FieldByName(ownerField).Interface())]
+		
+		if count.dest != nil {
+			count.dest[record.FieldByName(pk).Interface()] = total
+			continue
+		}
+		
+		target := record.FieldByName(count.field + "Count")
+		switch target.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			target.SetInt(total)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			target.SetUint(uint64(total))
+		default:
+			return fmt.Errorf("campo %sCount inteiro não encontrado em %s", count.field, record.Type().Name())
+		}
+	}
+	return nil
+}
@@ -2,8 +2,13 @@ package softdelete
 
 import (
 	"time"
+	
+	"github.com/Flavio-coutinho/Kiara-orm/types"
 )
 
+// Column é o nome da coluna usada para exclusão lógica
+const Column = "deleted_at"
+
 // SoftDelete representa os campos necessários para soft delete
 type SoftDelete struct {
 	DeletedAt *time.Time `db:"deleted_at"`
@@ -23,4 +28,14 @@ func (sd *SoftDelete) Delete() {
 // Restore restaura um registro deletado
 func (sd *SoftDelete) Restore() {
 	sd.DeletedAt = nil
-} 
\ No newline at end of file
+}
+
+// HasColumn verifica se a tabela possui a coluna de exclusão lógica
+func HasColumn(mapping *types.TableMapping) bool {
+	for _, field := range mapping.Fields {
+		if field.Name == Column {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
@@ -3,8 +3,9 @@ package tests
 import (
 	"context"
 	"testing"
-	"time"
 	
+	"github.com/Flavio-coutinho/kiara-orm/connection"
+	"github.com/Flavio-coutinho/kiara-orm/query"
 	"github.com/Flavio-coutinho/kiara-orm/session"
 	"github.com/Flavio-coutinho/kiara-orm/tests/config"
 	"github.com/Flavio-coutinho/kiara-orm/tests/models"
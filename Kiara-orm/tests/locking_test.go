@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	
+	"github.com/Flavio-coutinho/kiara-orm/query"
+	"github.com/Flavio-coutinho/kiara-orm/tests/models"
+	"github.com/Flavio-coutinho/kiara-orm/versioning"
+)
+
+func TestOptimisticLocking(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	
+	if err := sess.AutoMigrate(ctx, &models.Document{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	
+	doc := &models.Document{Title: "Rascunho"}
+	if err := sess.Model(&models.Document{}).Create(ctx, doc); err != nil {
+		t.Fatalf("Falha ao criar documento: %v", err)
+	}
+	
+	if doc.Version != 1 {
+		t.Errorf("Versão inicial esperada 1, recebida %d", doc.Version)
+	}
+	
+	// Duas cópias do mesmo registro editadas em paralelo
+	first := *doc
+	second := *doc
+	
+	first.Title = "Primeira edição"
+	if err := sess.Model(&models.Document{}).Save(ctx, &first); err != nil {
+		t.Fatalf("Falha ao salvar primeira edição: %v", err)
+	}
+	
+	if first.Version != 2 {
+		t.Errorf("Versão esperada 2, recebida %d", first.Version)
+	}
+	
+	second.Title = "Segunda edição"
+	err := sess.Model(&models.Document{}).Save(ctx, &second)
+	if !errors.Is(err, versioning.ErrStaleObject) {
+		t.Errorf("Esperado ErrStaleObject, recebido %v", err)
+	}
+}
+
+func TestBulkUpdateStaleRollback(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	
+	if err := sess.AutoMigrate(ctx, &models.Document{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	
+	fresh := &models.Document{Title: "Lote A"}
+	stale := &models.Document{Title: "Lote B"}
+	for _, doc := range []*models.Document{fresh, stale} {
+		if err := sess.Model(&models.Document{}).Create(ctx, doc); err != nil {
+			t.Fatalf("Falha ao criar documento: %v", err)
+		}
+	}
+	
+	// Outra edição avança a versão do segundo documento
+	concurrent := *stale
+	concurrent.Title = "Lote B editado"
+	if err := sess.Model(&models.Document{}).Save(ctx, &concurrent); err != nil {
+		t.Fatalf("Falha ao salvar edição concorrente: %v", err)
+	}
+	
+	fresh.Title = "Lote A atualizado"
+	stale.Title = "Lote B atualizado"
+	err := sess.Model(&models.Document{}).BulkUpdate(ctx, []interface{}{fresh, stale}, nil)
+	
+	var staleErr *versioning.StaleObjectError
+	if !errors.As(err, &staleErr) {
+		t.Fatalf("Esperado StaleObjectError, recebido %v", err)
+	}
+	
+	// O conflito desfaz também a atualização do primeiro documento
+	var docs []models.Document
+	if err := sess.Model(&models.Document{}).Find(ctx, &docs,
+		query.Condition{Column: "id", Operation: query.OpEq, Value: fresh.ID}); err != nil {
+		t.Fatalf("Falha ao buscar documento: %v", err)
+	}
+	if len(docs) != 1 || docs[0].Title != "Lote A" {
+		t.Errorf("Atualização do lote deveria ser desfeita, recebido %+v", docs)
+	}
+}
+
+func TestVersionedUpdateWithoutConditions(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	
+	if err := sess.AutoMigrate(ctx, &models.Document{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	
+	// Dois documentos novos compartilham a versão 1
+	first := &models.Document{Title: "Primeiro"}
+	second := &models.Document{Title: "Segundo"}
+	for _, doc := range []*models.Document{first, second} {
+		if err := sess.Model(&models.Document{}).Create(ctx, doc); err != nil {
+			t.Fatalf("Falha ao criar documento: %v", err)
+		}
+	}
+	
+	first.Title = "Primeiro editado"
+	if err := sess.Model(&models.Document{}).Update(ctx, first); err != nil {
+		t.Fatalf("Falha ao atualizar documento: %v", err)
+	}
+	
+	// Sem condições, a chave primária restringe a atualização ao próprio registro
+	var docs []models.Document
+	if err := sess.Model(&models.Document{}).Find(ctx, &docs,
+		query.Condition{Column: "id", Operation: query.OpEq, Value: second.ID}); err != nil {
+		t.Fatalf("Falha ao buscar documento: %v", err)
+	}
+	if len(docs) != 1 || docs[0].Title != "Segundo" || docs[0].Version != 1 {
+		t.Errorf("Segundo documento não deveria ser alterado, recebido %+v", docs)
+	}
+}
@@ -0,0 +1,333 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	
+	"github.com/Flavio-coutinho/kiara-orm/schema"
+)
+
+func TestParseSQLMigration(t *testing.T) {
+	content := `-- Cria a tabela de produtos
+-- +up
+CREATE TABLE products (
+  id INT PRIMARY KEY,
+  name VARCHAR(255)
+);
+CREATE INDEX idx_products_name ON products (name);
+
+-- +down
+DROP TABLE products;
+`
+
+	up, down, err := schema.ParseSQLMigration(content)
+	if err != nil {
+		t.Fatalf("Falha ao analisar migração: %v", err)
+	}
+	
+	expectedUp := "CREATE TABLE products (\n  id INT PRIMARY KEY,\n  name VARCHAR(255)\n);\nCREATE INDEX idx_products_name ON products (name);"
+	if up != expectedUp {
+		t.Errorf("Seção up esperada:\n%s\nrecebida:\n%s", expectedUp, up)
+	}
+	if down != "DROP TABLE products;" {
+		t.Errorf("Seção down inesperada: %q", down)
+	}
+	
+	if _, _, err := schema.ParseSQLMigration("CREATE TABLE x (id INT);"); err == nil {
+		t.Error("Comando fora das seções deveria gerar erro")
+	}
+}
+
+func TestVersionedMigrations(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	migrator := sess.Migrator()
+	
+	err := migrator.RegisterSQL(20240101000000, "create_products",
+		"CREATE TABLE products (id INT PRIMARY KEY, name VARCHAR(255));",
+		"DROP TABLE products;")
+	if err != nil {
+		t.Fatalf("Falha ao registrar migração: %v", err)
+	}
+	
+	err = migrator.Register(20240102000000, "add_products_price",
+		func(ctx context.Context, conn schema.Conn) error {
+			_, err := conn.ExecContext(ctx, "ALTER TABLE products ADD COLUMN price INT")
+			return err
+		},
+		func(ctx context.Context, conn schema.Conn) error {
+			_, err := conn.ExecContext(ctx, "ALTER TABLE products DROP COLUMN price")
+			return err
+		})
+	if err != nil {
+		t.Fatalf("Falha ao registrar migração: %v", err)
+	}
+	
+	if err := migrator.MigrateUp(ctx); err != nil {
+		t.Fatalf("Falha ao aplicar migrações: %v", err)
+	}
+	t.Cleanup(func() { _ = migrator.Rollback(ctx, 2) })
+	
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Falha ao obter status: %v", err)
+	}
+	for _, status := range statuses {
+		if !status.Applied {
+			t.Errorf("Migração %d deveria estar aplicada", status.Version)
+		}
+	}
+	
+	if err := migrator.Redo(ctx); err != nil {
+		t.Fatalf("Falha no redo: %v", err)
+	}
+	
+	if err := migrator.Rollback(ctx, 1); err != nil {
+		t.Fatalf("Falha no rollback: %v", err)
+	}
+	
+	statuses, err = migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Falha ao obter status: %v", err)
+	}
+	if len(statuses) != 2 || !statuses[0].Applied || statuses[1].Applied {
+		t.Errorf("Apenas a primeira migração deveria estar aplicada: %+v", statuses)
+	}
+}
+
+type planItem struct {
+	TableName struct{} `db:"plan_items"`
+	ID        int      `db:"id,primarykey,autoincrement"`
+	Name      string   `db:"name,size:100"`
+}
+
+func TestMigrationPlan(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	migrator := sess.Migrator()
+	
+	plan, err := migrator.PlanAutoMigrate(ctx, &planItem{})
+	if err != nil {
+		t.Fatalf("Falha ao planejar migração: %v", err)
+	}
+	if len(plan.Changes) != 1 || plan.Changes[0].Kind != schema.ChangeCreateTable {
+		t.Fatalf("Esperada apenas a criação da tabela: %+v", plan.Changes)
+	}
+	
+	info, err := migrator.Introspect(ctx, "plan_items")
+	if err != nil {
+		t.Fatalf("Falha na introspecção: %v", err)
+	}
+	if len(info.Columns) != 0 {
+		t.Fatal("O plano não deveria executar comandos")
+	}
+	
+	err = migrator.RegisterSQL(20240201000000, "drop_legacy",
+		"CREATE TABLE plan_legacy (id INT);\nDROP TABLE plan_legacy;", "")
+	if err != nil {
+		t.Fatalf("Falha ao registrar migração: %v", err)
+	}
+	
+	versioned, err := migrator.PlanMigrateUp(ctx)
+	if err != nil {
+		t.Fatalf("Falha ao planejar migrações versionadas: %v", err)
+	}
+	plan.Changes = append(plan.Changes, versioned.Changes...)
+	
+	if !plan.HasDestructive() {
+		t.Error("DROP TABLE deveria marcar o plano como destrutivo")
+	}
+	if statements := plan.Statements(); len(statements) != 3 {
+		t.Errorf("Esperado 3 comandos, obtido %q", statements)
+	}
+	
+	path := filepath.Join(t.TempDir(), "plan.sql")
+	if err := plan.WriteFile(path); err != nil {
+		t.Fatalf("Falha ao gravar plano: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Falha ao ler plano: %v", err)
+	}
+	for _, expected := range []string{"criar tabela plan_items", "DESTRUTIVA", "DROP TABLE plan_legacy;"} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Plano deveria conter %q:\n%s", expected, content)
+		}
+	}
+}
+
+func TestMigrationLock(t *testing.T) {
+	first, second := setupTestSession(t), setupTestSession(t)
+	ctx := context.Background()
+	
+	started, release := make(chan struct{}), make(chan struct{})
+	err := first.Migrator().Register(20240301000000, "slow_migration",
+		func(ctx context.Context, conn schema.Conn) error {
+			close(started)
+			<-release
+			return nil
+		},
+		func(ctx context.Context, conn schema.Conn) error { return nil })
+	if err != nil {
+		t.Fatalf("Falha ao registrar migração: %v", err)
+	}
+	
+	done := make(chan error, 1)
+	go func() { done <- first.Migrator().MigrateUp(ctx) }()
+	<-started
+	
+	// Enquanto a primeira réplica migra, a segunda aguarda o lock até o timeout
+	second.Migrator().SetOptions(schema.MigrateOptions{LockTimeout: 200 * time.Millisecond})
+	if err := second.Migrator().MigrateUp(ctx); err == nil {
+		t.Error("Migração concorrente deveria esperar o lock")
+	}
+	
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("Falha ao aplicar migração: %v", err)
+	}
+	t.Cleanup(func() { _ = first.Migrator().Rollback(ctx, 1) })
+	
+	if err := second.Migrator().MigrateUp(ctx); err != nil {
+		t.Errorf("Lock deveria estar liberado: %v", err)
+	}
+}
+
+// Tabela criada por AutoMigrate dentro de uma migração Go
+type nestedLockItem struct {
+	TableName struct{} `db:"nested_lock_items"`
+	ID        int      `db:"id,primarykey,autoincrement"`
+}
+
+func TestNestedMigrationLock(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	
+	// Sem reentrância, o AutoMigrate aninhado esperaria o próprio lock até o timeout
+	sess.Migrator().SetOptions(schema.MigrateOptions{LockTimeout: 500 * time.Millisecond})
+	err := sess.Migrator().Register(20240305000000, "nested_auto_migrate",
+		func(ctx context.Context, conn schema.Conn) error {
+			return sess.AutoMigrate(ctx, &nestedLockItem{})
+		},
+		func(ctx context.Context, conn schema.Conn) error {
+			_, err := conn.ExecContext(ctx, "DROP TABLE nested_lock_items")
+			return err
+		})
+	if err != nil {
+		t.Fatalf("Falha ao registrar migração: %v", err)
+	}
+	
+	if err := sess.Migrator().MigrateUp(ctx); err != nil {
+		t.Fatalf("AutoMigrate aninhado não deveria esperar o lock: %v", err)
+	}
+	t.Cleanup(func() { _ = sess.Migrator().Rollback(ctx, 1) })
+	
+	// O lock é liberado ao fim da execução externa
+	if err := sess.AutoMigrate(ctx, &nestedLockItem{}); err != nil {
+		t.Errorf("Lock deveria estar liberado: %v", err)
+	}
+}
+
+func TestMigrationChecksums(t *testing.T) {
+	applied, changed := setupTestSession(t), setupTestSession(t)
+	ctx := context.Background()
+	
+	err := applied.Migrator().RegisterSQL(20240401000000, "create_checksum_items",
+		"CREATE TABLE checksum_items (id INT PRIMARY KEY);", "DROP TABLE checksum_items;")
+	if err != nil {
+		t.Fatalf("Falha ao registrar migração: %v", err)
+	}
+	if err := applied.Migrator().MigrateUp(ctx); err != nil {
+		t.Fatalf("Falha ao aplicar migração: %v", err)
+	}
+	t.Cleanup(func() { _ = applied.Migrator().Rollback(ctx, 1) })
+	
+	// Mesma versão com o conteúdo alterado depois de aplicada
+	err = changed.Migrator().RegisterSQL(20240401000000, "create_checksum_items",
+		"CREATE TABLE checksum_items (id INT PRIMARY KEY, name VARCHAR(50));", "DROP TABLE checksum_items;")
+	if err != nil {
+		t.Fatalf("Falha ao registrar migração: %v", err)
+	}
+	
+	if err := changed.Migrator().MigrateUp(ctx); err == nil {
+		t.Error("Migração alterada deveria impedir o MigrateUp")
+	}
+	
+	statuses, err := changed.Migrator().Status(ctx)
+	if err != nil {
+		t.Fatalf("Falha ao obter status: %v", err)
+	}
+	if len(statuses) != 1 || !statuses[0].Modified {
+		t.Errorf("Migração deveria constar como alterada: %+v", statuses)
+	}
+	
+	changed.Migrator().SetOptions(schema.MigrateOptions{Checksums: schema.ChecksumWarn})
+	if err := changed.Migrator().MigrateUp(ctx); err != nil {
+		t.Errorf("Com ChecksumWarn a divergência não deveria falhar: %v", err)
+	}
+	
+	report, err := changed.Migrator().Verify(ctx, &planItem{})
+	if err != nil {
+		t.Fatalf("Falha ao verificar schema: %v", err)
+	}
+	if len(report.Migrations) != 1 || len(report.Changes) != 1 || !report.HasDrift() {
+		t.Errorf("Esperada a migração alterada e a tabela ausente: %s", report)
+	}
+}
+
+func TestGoMigrationChecksums(t *testing.T) {
+	applied, changed, unchecked := setupTestSession(t), setupTestSession(t), setupTestSession(t)
+	ctx := context.Background()
+	noop := func(ctx context.Context, conn schema.Conn) error { return nil }
+	
+	if err := applied.Migrator().RegisterChecksum(20240405000000, "go_checksum", "v1", noop, noop); err != nil {
+		t.Fatalf("Falha ao registrar migração: %v", err)
+	}
+	if err := applied.Migrator().MigrateUp(ctx); err != nil {
+		t.Fatalf("Falha ao aplicar migração: %v", err)
+	}
+	t.Cleanup(func() { _ = applied.Migrator().Rollback(ctx, 1) })
+	
+	if err := applied.Migrator().MigrateUp(ctx); err != nil {
+		t.Errorf("Migração Go inalterada não deveria falhar: %v", err)
+	}
+	
+	// Mesma versão com o checksum alterado junto com o código
+	if err := changed.Migrator().RegisterChecksum(20240405000000, "go_checksum", "v2", noop, noop); err != nil {
+		t.Fatalf("Falha ao registrar migração: %v", err)
+	}
+	if err := changed.Migrator().MigrateUp(ctx); err == nil {
+		t.Error("Migração Go alterada deveria impedir o MigrateUp")
+	}
+	
+	// Sem checksum, a migração aplicada não é comparada
+	if err := unchecked.Migrator().Register(20240405000000, "go_checksum", noop, noop); err != nil {
+		t.Fatalf("Falha ao registrar migração: %v", err)
+	}
+	if err := unchecked.Migrator().MigrateUp(ctx); err != nil {
+		t.Errorf("Migração Go sem checksum não deveria impedir o MigrateUp: %v", err)
+	}
+}
+
+func TestGoMigrationWithoutChecksum(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	noop := func(ctx context.Context, conn schema.Conn) error { return nil }
+	
+	if err := sess.Migrator().Register(20240406000000, "go_without_checksum", noop, noop); err != nil {
+		t.Fatalf("Falha ao registrar migração: %v", err)
+	}
+	if err := sess.Migrator().MigrateUp(ctx); err != nil {
+		t.Fatalf("Falha ao aplicar migração: %v", err)
+	}
+	t.Cleanup(func() { _ = sess.Migrator().Rollback(ctx, 1) })
+	
+	// Com ChecksumFail, a segunda execução não deve tratar a migração como alterada
+	if err := sess.Migrator().MigrateUp(ctx); err != nil {
+		t.Errorf("Migração Go sem checksum não deveria impedir o MigrateUp: %v", err)
+	}
+}
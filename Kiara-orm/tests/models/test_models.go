@@ -3,12 +3,14 @@ package models
 import "time"
 
 type User struct {
-	ID        int       `db:"id,primarykey,autoincrement"`
-	Name      string    `db:"name,size:255" validate:"required,min=3"`
-	Email     string    `db:"email,unique" validate:"required,email"`
-	Age       int       `db:"age" validate:"min=18"`
-	CreatedAt time.Time `db:"created_at"`
+	ID        int        `db:"id,primarykey,autoincrement"`
+	Name      string     `db:"name,size:255" validate:"required,min=3"`
+	Email     string     `db:"email,unique" validate:"required,email"`
+	Age       int        `db:"age" validate:"min=18"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
 	DeletedAt *time.Time `db:"deleted_at"`
+	Posts     []Post     `rel:"has_many,fk:user_id"`
 }
 
 type Post struct {
@@ -16,5 +18,45 @@ type Post struct {
 	Title   string `db:"title,size:255" validate:"required"`
 	Content string `db:"content"`
 	UserID  int    `db:"user_id"`
-	User    *User  `db:"-"`
-} 
\ No newline at end of file
+	User    *User  `rel:"belongs_to,fk:user_id"`
+}
+
+type Document struct {
+	ID       int       `db:"id,primarykey,autoincrement"`
+	Title    string    `db:"title,size:255"`
+	Version  int       `db:"version,version"`
+	Comments []Comment `rel:"has_many,polymorphic:Commentable,polymorphicValue:documents"`
+}
+
+type Comment struct {
+	ID              int    `db:"id,primarykey,autoincrement"`
+	Body            string `db:"body"`
+	CommentableID   int    `db:"commentable_id"`
+	CommentableType string `db:"commentable_type,size:50"`
+}
+
+type Author struct {
+	ID   int    `db:"id,primarykey,autoincrement"`
+	Name string `db:"name,size:255"`
+	Tags []Tag  `rel:"many2many,join:author_tags"`
+}
+
+type Article struct {
+	ID       int     `db:"id,primarykey,autoincrement"`
+	Title    string  `db:"title,size:255"`
+	AuthorID int     `db:"author_id"`
+	Author   *Author `rel:"belongs_to"`
+}
+
+type Tag struct {
+	ID        int        `db:"id,primarykey,autoincrement"`
+	Name      string     `db:"name,size:100"`
+	DeletedAt *time.Time `db:"deleted_at"`
+}
+
+// AuthorTag é a tabela de junção entre Author e Tag
+type AuthorTag struct {
+	TableName struct{} `db:"author_tags"`
+	AuthorID  int      `db:"author_id"`
+	TagID     int      `db:"tag_id"`
+}
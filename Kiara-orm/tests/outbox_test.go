@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	
+	"github.com/Flavio-coutinho/kiara-orm/outbox"
+	"github.com/Flavio-coutinho/kiara-orm/session"
+	"github.com/Flavio-coutinho/kiara-orm/tests/models"
+	"github.com/Flavio-coutinho/kiara-orm/transaction"
+)
+
+func TestOutbox(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	
+	if err := sess.AutoMigrate(ctx, &models.Document{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	if err := outbox.Migrate(ctx, sess); err != nil {
+		t.Fatalf("Falha ao criar tabela de outbox: %v", err)
+	}
+	
+	// Evento gravado em transação desfeita não deve ser publicado
+	_ = sess.Transaction(ctx, func(tx *session.Session) error {
+		if err := tx.Model(&models.Document{}).Create(ctx, &models.Document{Title: "Descartado"}); err != nil {
+			return err
+		}
+		if err := outbox.Enqueue(ctx, tx, &outbox.Event{Topic: "document.discarded", Payload: "{}"}); err != nil {
+			return err
+		}
+		return errors.New("desfazer")
+	})
+	
+	err := sess.Transaction(ctx, func(tx *session.Session) error {
+		if err := tx.Model(&models.Document{}).Create(ctx, &models.Document{Title: "Publicado"}); err != nil {
+			return err
+		}
+		return outbox.Enqueue(ctx, tx, &outbox.Event{Topic: "document.created", Payload: `{"title":"Publicado"}`})
+	})
+	if err != nil {
+		t.Fatalf("Falha na transação: %v", err)
+	}
+	
+	var published []string
+	failing := true
+	publisher := outbox.PublisherFunc(func(ctx context.Context, event outbox.Event) error {
+		if failing {
+			return errors.New("broker indisponível")
+		}
+		published = append(published, event.Topic)
+		return nil
+	})
+	
+	options := outbox.DefaultRelayOptions()
+	options.Retry = transaction.RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	relay := outbox.NewRelay(sess, publisher, options)
+	
+	// Primeira tentativa falha e agenda uma nova
+	if _, err := relay.ProcessBatch(ctx); err != nil {
+		t.Fatalf("Falha ao processar outbox: %v", err)
+	}
+	
+	failing = false
+	time.Sleep(10 * time.Millisecond)
+	
+	if _, err := relay.ProcessBatch(ctx); err != nil {
+		t.Fatalf("Falha ao processar outbox: %v", err)
+	}
+	
+	if len(published) != 1 || published[0] != "document.created" {
+		t.Errorf("Esperado apenas document.created, publicado %v", published)
+	}
+	
+	var events []outbox.Event
+	if err := sess.Model(&outbox.Event{}).Find(ctx, &events); err != nil {
+		t.Fatalf("Falha ao buscar eventos: %v", err)
+	}
+	for _, event := range events {
+		if event.Topic == "document.created" && (event.Status != outbox.StatusSent || event.Attempts != 1) {
+			t.Errorf("Evento deveria estar enviado após uma falha: %+v", event)
+		}
+	}
+}
+
+func TestRelayClock(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	
+	if err := outbox.Migrate(ctx, sess); err != nil {
+		t.Fatalf("Falha ao criar tabela de outbox: %v", err)
+	}
+	
+	// Relógio adiantado: eventos pendentes de outros testes também ficam vencidos
+	now := time.Now().UTC().Truncate(time.Second).Add(24 * time.Hour)
+	sess.SetClock(func() time.Time { return now })
+	
+	if err := outbox.Enqueue(ctx, sess, &outbox.Event{Topic: "relay.clock", Payload: "{}"}); err != nil {
+		t.Fatalf("Falha ao gravar evento: %v", err)
+	}
+	
+	deliveries, failing := 0, true
+	publisher := outbox.PublisherFunc(func(ctx context.Context, event outbox.Event) error {
+		if event.Topic != "relay.clock" {
+			return nil
+		}
+		if failing {
+			return errors.New("broker indisponível")
+		}
+		deliveries++
+		return nil
+	})
+	
+	// Opções zeradas recebem os padrões: BatchSize 0 não processaria nada
+	relay := outbox.NewRelay(sess, publisher, outbox.RelayOptions{})
+	if processed, err := relay.ProcessBatch(ctx); err != nil || processed == 0 {
+		t.Fatalf("Esperado processar o evento, processados %d: %v", processed, err)
+	}
+	
+	// Mesmo instante: a nova tentativa ainda não venceu
+	failing = false
+	if _, err := relay.ProcessBatch(ctx); err != nil {
+		t.Fatalf("Falha ao processar outbox: %v", err)
+	}
+	if deliveries != 0 {
+		t.Error("Evento não deveria ser reenviado antes do backoff")
+	}
+	
+	// Após a espera máxima da política padrão, o evento é entregue
+	now = now.Add(outbox.DefaultRelayOptions().Retry.MaxDelay + time.Second)
+	if _, err := relay.ProcessBatch(ctx); err != nil {
+		t.Fatalf("Falha ao processar outbox: %v", err)
+	}
+	if deliveries != 1 {
+		t.Errorf("Esperada uma entrega após o backoff, recebidas %d", deliveries)
+	}
+}
@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"testing"
+	
+	"github.com/Flavio-coutinho/kiara-orm/dialect"
+	"github.com/Flavio-coutinho/kiara-orm/query"
+)
+
+func TestExistsSubquery(t *testing.T) {
+	d := dialect.NewPostgreSQL()
+	
+	sub := query.NewBuilder(d).
+		Table("posts").
+		SelectRaw("1").
+		WhereColumn("posts.user_id", query.OpEq, "users.id").
+		Where("posts.title", query.OpEq, "Test Post")
+	
+	sql, params := query.NewBuilder(d).
+		Table("users").
+		Where("age", query.OpGe, 18).
+		WhereExists(sub).
+		BuildSelect()
+	
+	expected := `SELECT * FROM "users" WHERE "age" >= $1 AND EXISTS (SELECT 1 FROM "posts" WHERE "posts"."user_id" = "users"."id" AND "posts"."title" = $2)`
+	if sql != expected {
+		t.Errorf("SQL esperado:\n%s\nrecebido:\n%s", expected, sql)
+	}
+	
+	if len(params) != 2 || params[0] != 18 || params[1] != "Test Post" {
+		t.Errorf("Parâmetros inesperados: %v", params)
+	}
+}
+
+func TestForUpdateSkipLocked(t *testing.T) {
+	build := func(d dialect.Dialect) string {
+		sql, _ := query.NewBuilder(d).
+			Table("jobs").
+			Where("status", query.OpEq, "pending").
+			Limit(10).
+			ForUpdate(true).
+			BuildSelect()
+		return sql
+	}
+	
+	expected := `SELECT * FROM "jobs" WHERE "status" = $1 LIMIT 10 FOR UPDATE SKIP LOCKED`
+	if sql := build(dialect.NewPostgreSQL()); sql != expected {
+		t.Errorf("SQL esperado:\n%s\nrecebido:\n%s", expected, sql)
+	}
+	
+	// SQLite não suporta bloqueio de linhas
+	expected = `SELECT * FROM "jobs" WHERE "status" = ? LIMIT 10`
+	if sql := build(dialect.NewSQLite()); sql != expected {
+		t.Errorf("SQL esperado:\n%s\nrecebido:\n%s", expected, sql)
+	}
+}
@@ -3,17 +3,71 @@ package tests
 import (
 	"context"
 	"testing"
+	"time"
 	
 	"github.com/Flavio-coutinho/kiara-orm/query"
+	"github.com/Flavio-coutinho/kiara-orm/schema"
+	"github.com/Flavio-coutinho/kiara-orm/session"
 	"github.com/Flavio-coutinho/kiara-orm/tests/models"
+	"github.com/Flavio-coutinho/kiara-orm/types"
 )
 
+func TestRelationTags(t *testing.T) {
+	mapping, err := schema.NewParser().Parse(&models.Post{})
+	if err != nil {
+		t.Fatalf("Falha ao analisar modelo: %v", err)
+	}
+	
+	if len(mapping.Relations) != 1 {
+		t.Fatalf("Esperado 1 relacionamento, recebido %d", len(mapping.Relations))
+	}
+	
+	rel := mapping.Relations[0]
+	if rel.Kind != types.RelBelongsTo || rel.FieldName != "User" || rel.ForeignKey != "user_id" {
+		t.Errorf("Relacionamento inesperado: %+v", rel)
+	}
+	
+	for _, field := range mapping.Fields {
+		if field.FieldName == "User" {
+			t.Error("Campo de relacionamento não deveria ser mapeado como coluna")
+		}
+	}
+}
+
+func TestPolymorphicRelationTags(t *testing.T) {
+	mapping, err := schema.NewParser().Parse(&models.Document{})
+	if err != nil {
+		t.Fatalf("Falha ao analisar modelo: %v", err)
+	}
+	
+	if len(mapping.Relations) != 1 {
+		t.Fatalf("Esperado 1 relacionamento, recebido %d", len(mapping.Relations))
+	}
+	
+	rel := mapping.Relations[0]
+	if rel.ForeignKey != "CommentableID" || rel.PolymorphicType != "CommentableType" || rel.PolymorphicValue != "documents" {
+		t.Errorf("Relacionamento polimórfico inesperado: %+v", rel)
+	}
+	
+	if _, err := schema.NewParser().Parse(&struct {
+		ID      int      `db:"id,primarykey"`
+		Comment *models.Comment `rel:"belongs_to,polymorphic:Commentable"`
+	}{}); err == nil {
+		t.Error("Esperado erro para belongs_to polimórfico")
+	}
+}
+
 func TestRelations(t *testing.T) {
 	sess := setupTestSession(t)
 	ctx := context.Background()
 	
 	// Setup relationships
-	sess.HasOne(&models.Post{}, "User", &models.User{}, "UserID")
+	if err := sess.BelongsTo(&models.Post{}, "User", &models.User{}, "UserID"); err != nil {
+		t.Fatalf("Falha ao definir relacionamento: %v", err)
+	}
+	if err := sess.HasMany(&models.User{}, "Posts", &models.Post{}, "user_id"); err != nil {
+		t.Fatalf("Falha ao definir relacionamento: %v", err)
+	}
 	
 	// Create test data
 	user := &models.User{
@@ -58,4 +112,232 @@ func TestRelations(t *testing.T) {
 			t.Errorf("Nome do usuário esperado 'Jane Doe', recebido '%s'", loadedPost.User.Name)
 		}
 	})
-} 
\ No newline at end of file
+	
+	// Test de preload via JOIN
+	t.Run("Joins", func(t *testing.T) {
+		var posts []models.Post
+		err := sess.Model(&models.Post{}).
+			Joins("User").
+			Find(ctx, &posts,
+				query.Condition{Column: "id", Operation: query.OpEq, Value: post.ID})
+		
+		if err != nil {
+			t.Fatalf("Falha ao carregar post com JOIN: %v", err)
+		}
+		
+		if len(posts) != 1 || posts[0].User == nil {
+			t.Fatalf("Usuário não foi carregado via JOIN: %+v", posts)
+		}
+		
+		if posts[0].User.Name != "Jane Doe" || posts[0].Title != "Test Post" {
+			t.Errorf("Dados inesperados: post %+v, usuário %+v", posts[0], posts[0].User)
+		}
+	})
+	
+	// Test preload de um-para-muitos em um slice
+	t.Run("PreloadMany", func(t *testing.T) {
+		var users []models.User
+		err := sess.Model(&models.User{}).
+			Preload("Posts").
+			Find(ctx, &users,
+				query.Condition{Column: "id", Operation: query.OpEq, Value: user.ID})
+		
+		if err != nil {
+			t.Fatalf("Falha ao carregar usuários com posts: %v", err)
+		}
+		
+		if len(users) != 1 || len(users[0].Posts) != 1 {
+			t.Fatalf("Esperado 1 usuário com 1 post, recebido %+v", users)
+		}
+		
+		if users[0].Posts[0].Title != "Test Post" {
+			t.Errorf("Título esperado 'Test Post', recebido '%s'", users[0].Posts[0].Title)
+		}
+	})
+	
+	// Test preload com condições
+	t.Run("PreloadWith", func(t *testing.T) {
+		var loadedUser models.User
+		err := sess.Model(&models.User{}).
+			PreloadWith("Posts", session.PreloadOptions{
+				Conditions: []query.Condition{
+					{Column: "title", Operation: query.OpEq, Value: "Outro Post"},
+				},
+			}).
+			Find(ctx, &loadedUser,
+				query.Condition{Column: "id", Operation: query.OpEq, Value: user.ID})
+		
+		if err != nil {
+			t.Fatalf("Falha ao carregar usuário: %v", err)
+		}
+		
+		if loadedUser.Posts == nil || len(loadedUser.Posts) != 0 {
+			t.Errorf("Esperado nenhum post com o filtro, recebido %d", len(loadedUser.Posts))
+		}
+	})
+	
+	// Test da API de associação
+	t.Run("Association", func(t *testing.T) {
+		assoc := sess.Model(user).Association("Posts")
+		
+		extra := &models.Post{Title: "Outro Post", Content: "Conteúdo"}
+		if err := assoc.Append(ctx, extra); err != nil {
+			t.Fatalf("Falha ao associar post: %v", err)
+		}
+		
+		if extra.UserID != user.ID {
+			t.Errorf("Chave estrangeira esperada %d, recebida %d", user.ID, extra.UserID)
+		}
+		
+		count, err := assoc.Count(ctx)
+		if err != nil {
+			t.Fatalf("Falha ao contar posts: %v", err)
+		}
+		if count != 2 {
+			t.Errorf("Esperado 2 posts associados, recebido %d", count)
+		}
+		
+		if err := assoc.Delete(ctx, extra); err != nil {
+			t.Fatalf("Falha ao desassociar post: %v", err)
+		}
+		
+		if count, _ := assoc.Count(ctx); count != 1 {
+			t.Errorf("Esperado 1 post associado, recebido %d", count)
+		}
+	})
+	
+	// Test de filtros de existência e contagem de relacionamentos
+	t.Run("WhereHasWithCount", func(t *testing.T) {
+		var users []models.User
+		counts := make(map[interface{}]int64)
+		err := sess.Model(&models.User{}).
+			WhereHas("Posts", query.Condition{Column: "title", Operation: query.OpEq, Value: "Test Post"}).
+			WithCountMap("Posts", counts).
+			Find(ctx, &users)
+		
+		if err != nil {
+			t.Fatalf("Falha ao filtrar usuários: %v", err)
+		}
+		
+		if len(users) != 1 || users[0].ID != user.ID {
+			t.Fatalf("Esperado apenas o usuário %d, recebido %+v", user.ID, users)
+		}
+		
+		if counts[user.ID] != 1 {
+			t.Errorf("Esperado 1 post contado, recebido %d", counts[user.ID])
+		}
+		
+		users = nil
+		err = sess.Model(&models.User{}).
+			WhereDoesntHave("Posts").
+			Find(ctx, &users, query.Condition{Column: "id", Operation: query.OpEq, Value: user.ID})
+		
+		if err != nil {
+			t.Fatalf("Falha ao filtrar usuários: %v", err)
+		}
+		
+		if len(users) != 0 {
+			t.Errorf("Esperado nenhum usuário sem posts, recebido %d", len(users))
+		}
+	})
+}
+func TestNestedPreloadSharedParent(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	
+	if err := sess.AutoMigrate(ctx, &models.Author{}, &models.Article{}, &models.Tag{}, &models.AuthorTag{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	
+	author := &models.Author{Name: "Ana"}
+	if err := sess.Model(&models.Author{}).Create(ctx, author); err != nil {
+		t.Fatalf("Falha ao criar autor: %v", err)
+	}
+	if err := sess.Model(author).Association("Tags").Append(ctx, &models.Tag{Name: "go"}, &models.Tag{Name: "sql"}); err != nil {
+		t.Fatalf("Falha ao associar tags: %v", err)
+	}
+	
+	for _, title := range []string{"Primeiro", "Segundo"} {
+		if err := sess.Model(&models.Article{}).Create(ctx, &models.Article{Title: title, AuthorID: author.ID}); err != nil {
+			t.Fatalf("Falha ao criar artigo: %v", err)
+		}
+	}
+	
+	var articles []models.Article
+	err := sess.Model(&models.Article{}).
+		Preload("Author.Tags").
+		Find(ctx, &articles, query.Condition{Column: "author_id", Operation: query.OpEq, Value: author.ID})
+	if err != nil {
+		t.Fatalf("Falha ao carregar artigos: %v", err)
+	}
+	
+	if len(articles) != 2 || articles[0].Author == nil {
+		t.Fatalf("Esperados 2 artigos com autor, recebido %+v", articles)
+	}
+	
+	// Os artigos compartilham o autor, cujas tags são carregadas uma única vez
+	if tags := articles[0].Author.Tags; len(tags) != 2 {
+		t.Errorf("Esperadas 2 tags, recebido %+v", tags)
+	}
+}
+
+func TestManyToManyAssociationCount(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	
+	if err := sess.AutoMigrate(ctx, &models.Author{}, &models.Article{}, &models.Tag{}, &models.AuthorTag{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	
+	author := &models.Author{Name: "Bia"}
+	if err := sess.Model(&models.Author{}).Create(ctx, author); err != nil {
+		t.Fatalf("Falha ao criar autor: %v", err)
+	}
+	
+	kept, trashed := &models.Tag{Name: "mantida"}, &models.Tag{Name: "excluída"}
+	assoc := sess.Model(author).Association("Tags")
+	if err := assoc.Append(ctx, kept, trashed); err != nil {
+		t.Fatalf("Falha ao associar tags: %v", err)
+	}
+	
+	now := time.Now()
+	trashed.DeletedAt = &now
+	if err := sess.Model(&models.Tag{}).Save(ctx, trashed); err != nil {
+		t.Fatalf("Falha ao excluir tag: %v", err)
+	}
+	
+	// A tag excluída logicamente continua na tabela de junção, mas não é contada
+	count, err := assoc.Count(ctx)
+	if err != nil {
+		t.Fatalf("Falha ao contar tags: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Esperada 1 tag associada, recebido %d", count)
+	}
+}
+
+func TestBelongsToEmptyReplace(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	
+	if err := sess.AutoMigrate(ctx, &models.Author{}, &models.Article{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	
+	author := &models.Author{Name: "Caio"}
+	if err := sess.Model(&models.Author{}).Create(ctx, author); err != nil {
+		t.Fatalf("Falha ao criar autor: %v", err)
+	}
+	article := &models.Article{Title: "Sem autor", AuthorID: author.ID}
+	if err := sess.Model(&models.Article{}).Create(ctx, article); err != nil {
+		t.Fatalf("Falha ao criar artigo: %v", err)
+	}
+	
+	// Replace sem registros desassocia, como Clear
+	if err := sess.Model(article).Association("Author").Replace(ctx); err != nil {
+		t.Fatalf("Falha ao substituir autor: %v", err)
+	}
+	if article.AuthorID != 0 || article.Author != nil {
+		t.Errorf("Artigo deveria ficar sem autor, recebido %+v", article)
+	}
+}
@@ -0,0 +1,485 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	
+	"github.com/Flavio-coutinho/kiara-orm/dialect"
+	"github.com/Flavio-coutinho/kiara-orm/schema"
+	"github.com/Flavio-coutinho/kiara-orm/session"
+	"github.com/Flavio-coutinho/kiara-orm/types"
+)
+
+// Duas versões do mesmo modelo para comparar o schema
+type productV1 struct {
+	TableName struct{} `db:"diff_products"`
+	ID        int      `db:"id,primarykey,autoincrement"`
+	Name      string   `db:"name,size:100"`
+	Legacy    string   `db:"legacy,size:50"`
+}
+
+type productV2 struct {
+	TableName struct{} `db:"diff_products"`
+	ID        int      `db:"id,primarykey,autoincrement"`
+	Name      string   `db:"name,size:50"`
+	Sku       *string  `db:"sku,size:40,unique"`
+}
+
+func TestSchemaDiff(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	migrator := sess.Migrator()
+	
+	if err := sess.AutoMigrate(ctx, &productV1{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	
+	changes, err := migrator.Diff(ctx, &productV1{})
+	if err != nil {
+		t.Fatalf("Falha ao calcular diferenças: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Modelo migrado não deveria ter diferenças: %+v", changes)
+	}
+	
+	changes, err = migrator.Diff(ctx, &productV2{})
+	if err != nil {
+		t.Fatalf("Falha ao calcular diferenças: %v", err)
+	}
+	
+	expected := map[schema.ChangeKind]bool{
+		schema.ChangeAlterColumn: true, // VARCHAR(100) → VARCHAR(50) reduz o tipo
+		schema.ChangeAddColumn:   false,
+		schema.ChangeDropColumn:  true,
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Esperado %d alterações, obtido %+v", len(expected), changes)
+	}
+	for _, change := range changes {
+		destructive, ok := expected[change.Kind]
+		if !ok {
+			t.Errorf("Alteração inesperada: %s", change.Description)
+			continue
+		}
+		if change.Destructive != destructive {
+			t.Errorf("%s: destrutiva esperada %v", change.Description, destructive)
+		}
+	}
+	
+	// Sem AllowDestructive apenas a coluna nova é criada
+	if err := sess.AutoMigrate(ctx, &productV2{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	info, err := migrator.Introspect(ctx, "diff_products")
+	if err != nil {
+		t.Fatalf("Falha na introspecção: %v", err)
+	}
+	if sku, ok := info.Column("sku"); !ok || !sku.Unique || sku.Size != 40 {
+		t.Errorf("Coluna sku esperada única com tamanho 40: %+v", sku)
+	}
+	if _, ok := info.Column("legacy"); !ok {
+		t.Error("Coluna legacy não deveria ser removida sem AllowDestructive")
+	}
+	
+	migrator.SetOptions(schema.MigrateOptions{AllowDestructive: true})
+	if err := sess.AutoMigrate(ctx, &productV2{}); err != nil {
+		t.Fatalf("Falha na migração destrutiva: %v", err)
+	}
+	
+	changes, err = migrator.Diff(ctx, &productV2{})
+	if err != nil {
+		t.Fatalf("Falha ao calcular diferenças: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Não deveriam restar diferenças: %+v", changes)
+	}
+}
+
+func TestSQLiteColumnSQL(t *testing.T) {
+	d := dialect.NewSQLite()
+	
+	if sql := d.DropColumnSQL("products", "legacy"); sql != `ALTER TABLE "products" DROP COLUMN "legacy"` {
+		t.Errorf("DROP COLUMN nativo esperado, recebido %s", sql)
+	}
+	
+	// Alterações de coluna no SQLite exigem a recriação da tabela pelo Migrator
+	field := types.FieldMapping{Name: "name", Type: types.Text, Size: 50}
+	if statements := d.AlterColumnSQL("products", field); statements != nil {
+		t.Errorf("SQLite não deveria gerar ALTER COLUMN: %v", statements)
+	}
+}
+
+// Pedidos referenciam clientes pelo relacionamento e itens referenciam pedidos pela tag
+type fkCustomer struct {
+	TableName struct{} `db:"fk_customers"`
+	ID        int      `db:"id,primarykey,autoincrement"`
+	Name      string   `db:"name,size:100"`
+}
+
+type fkOrder struct {
+	TableName  struct{}    `db:"fk_orders"`
+	ID         int         `db:"id,primarykey,autoincrement"`
+	CustomerID int         `db:"customer_id"`
+	Customer   *fkCustomer `rel:"belongs_to,fk:customer_id,onDelete:cascade"`
+}
+
+type fkOrderItem struct {
+	TableName struct{} `db:"fk_order_items"`
+	ID        int      `db:"id,primarykey,autoincrement"`
+	OrderID   *int     `db:"order_id,fk:fk_orders.id,onDelete:set null"`
+}
+
+func TestForeignKeyMapping(t *testing.T) {
+	mapping, err := schema.NewParser().Parse(&fkOrderItem{})
+	if err != nil {
+		t.Fatalf("Falha ao analisar modelo: %v", err)
+	}
+	
+	expected := types.ForeignKeyMapping{
+		Name:            "fk_fk_order_items_order_id",
+		Column:          "order_id",
+		ReferenceTable:  "fk_orders",
+		ReferenceColumn: "id",
+		OnDelete:        "SET NULL",
+	}
+	if len(mapping.ForeignKeys) != 1 || mapping.ForeignKeys[0] != expected {
+		t.Fatalf("Chave estrangeira esperada %+v, recebida %+v", expected, mapping.ForeignKeys)
+	}
+	
+	mapping, err = schema.NewParser().Parse(&fkOrder{})
+	if err != nil {
+		t.Fatalf("Falha ao analisar modelo: %v", err)
+	}
+	if len(mapping.ForeignKeys) != 1 || mapping.ForeignKeys[0].ReferenceTable != "fk_customers" || mapping.ForeignKeys[0].OnDelete != "CASCADE" {
+		t.Fatalf("Chave estrangeira do belongs_to inesperada: %+v", mapping.ForeignKeys)
+	}
+	
+	sql := dialect.NewPostgreSQL().CreateTableSQL(*mapping)
+	if !strings.Contains(sql, `CONSTRAINT "fk_fk_orders_customer_id" FOREIGN KEY ("customer_id") REFERENCES "fk_customers" ("id") ON DELETE CASCADE`) {
+		t.Errorf("CREATE TABLE sem a chave estrangeira:\n%s", sql)
+	}
+}
+
+// Chave primária e relacionamento declarados em structs base embutidas
+type FkBaseModel struct {
+	ID int `db:"id,primarykey,autoincrement"`
+}
+
+type fkTenant struct {
+	TableName struct{} `db:"fk_tenants"`
+	FkBaseModel
+	Name string `db:"name,size:100"`
+}
+
+type FkTenantOwned struct {
+	TenantID int       `db:"tenant_id"`
+	Tenant   *fkTenant `rel:"belongs_to,fk:tenant_id,constraint"`
+}
+
+type fkProject struct {
+	TableName struct{} `db:"fk_projects"`
+	ID        int      `db:"id,primarykey,autoincrement"`
+	FkTenantOwned
+}
+
+func TestEmbeddedForeignKeyMapping(t *testing.T) {
+	mapping, err := schema.NewParser().Parse(&fkProject{})
+	if err != nil {
+		t.Fatalf("Falha ao analisar modelo: %v", err)
+	}
+	
+	if len(mapping.Relations) != 1 || mapping.Relations[0].FieldName != "Tenant" {
+		t.Errorf("Relacionamento da struct embutida ausente: %+v", mapping.Relations)
+	}
+	
+	expected := types.ForeignKeyMapping{
+		Name:            "fk_fk_projects_tenant_id",
+		Column:          "tenant_id",
+		ReferenceTable:  "fk_tenants",
+		ReferenceColumn: "id",
+	}
+	if len(mapping.ForeignKeys) != 1 || mapping.ForeignKeys[0] != expected {
+		t.Fatalf("Chave estrangeira esperada %+v, recebida %+v", expected, mapping.ForeignKeys)
+	}
+}
+
+// belongs_to com constraint sem a coluna da chave estrangeira
+type fkBroken struct {
+	TableName struct{}    `db:"fk_broken"`
+	ID        int         `db:"id,primarykey,autoincrement"`
+	Customer  *fkCustomer `rel:"belongs_to,constraint"`
+}
+
+func TestModelParseError(t *testing.T) {
+	// Sem banco: o erro de análise deve ser retornado antes de qualquer consulta
+	sess := session.NewSession(nil, dialect.NewMySQL())
+	
+	err := sess.Model(&fkBroken{}).Create(context.Background(), &fkBroken{})
+	if err == nil || !strings.Contains(err.Error(), "CustomerID") {
+		t.Errorf("Esperado erro de análise do modelo, recebido %v", err)
+	}
+}
+
+func TestForeignKeyMigration(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	migrator := sess.Migrator()
+	
+	// Os modelos fora de ordem são criados das tabelas referenciadas para as dependentes
+	plan, err := migrator.PlanAutoMigrate(ctx, &fkOrderItem{}, &fkOrder{}, &fkCustomer{})
+	if err != nil {
+		t.Fatalf("Falha ao planejar migração: %v", err)
+	}
+	var tables []string
+	for _, change := range plan.Changes {
+		tables = append(tables, change.Table)
+	}
+	if strings.Join(tables, ",") != "fk_customers,fk_orders,fk_order_items" {
+		t.Errorf("Ordem de criação inesperada: %v", tables)
+	}
+	
+	if err := sess.AutoMigrate(ctx, &fkOrderItem{}, &fkOrder{}, &fkCustomer{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	
+	info, err := migrator.Introspect(ctx, "fk_orders")
+	if err != nil {
+		t.Fatalf("Falha na introspecção: %v", err)
+	}
+	fk, ok := info.ForeignKey("customer_id")
+	if !ok || fk.ReferenceTable != "fk_customers" || fk.OnDelete != "CASCADE" {
+		t.Errorf("Chave estrangeira de fk_orders não criada: %+v", info.ForeignKeys)
+	}
+	
+	for _, model := range []interface{}{&fkCustomer{}, &fkOrder{}, &fkOrderItem{}} {
+		changes, err := migrator.Diff(ctx, model)
+		if err != nil {
+			t.Fatalf("Falha ao calcular diferenças: %v", err)
+		}
+		if len(changes) != 0 {
+			t.Errorf("Modelo migrado não deveria ter diferenças: %+v", changes)
+		}
+	}
+}
+
+// Índice composto ordenado por priority, índice único parcial e índice simples
+type indexedAccount struct {
+	TableName struct{}   `db:"indexed_accounts"`
+	ID        int        `db:"id,primarykey,autoincrement"`
+	Email     string     `db:"email,size:100,index:idx_tenant_email,priority:2,uniqueIndex:uq_accounts_email,where:deleted_at IS NULL"`
+	TenantID  int        `db:"tenant_id,index:idx_tenant_email,priority:1"`
+	Name      string     `db:"name,size:100,index"`
+	DeletedAt *time.Time `db:"deleted_at"`
+}
+
+func TestIndexMapping(t *testing.T) {
+	mapping, err := schema.NewParser().Parse(&indexedAccount{})
+	if err != nil {
+		t.Fatalf("Falha ao analisar modelo: %v", err)
+	}
+	
+	if len(mapping.Indexes) != 3 {
+		t.Fatalf("Esperados 3 índices, obtido %+v", mapping.Indexes)
+	}
+	
+	composite := mapping.Indexes[0]
+	if composite.Name != "idx_tenant_email" || strings.Join(composite.Columns, ",") != "tenant_id,email" {
+		t.Errorf("Índice composto inesperado: %+v", composite)
+	}
+	if unique := mapping.Indexes[1]; !unique.Unique || unique.Where != "deleted_at IS NULL" {
+		t.Errorf("Índice único parcial inesperado: %+v", unique)
+	}
+	if simple := mapping.Indexes[2]; simple.Name != "idx_indexed_accounts_name" {
+		t.Errorf("Nome padrão inesperado: %s", simple.Name)
+	}
+	
+	index := types.IndexMapping{Name: "idx_docs_tags", Columns: []string{"tags"}, Method: "GIN", Where: "tags IS NOT NULL"}
+	expected := `CREATE INDEX "idx_docs_tags" ON "docs" USING GIN ("tags") WHERE tags IS NOT NULL`
+	if sql := dialect.NewPostgreSQL().IndexSQL("docs", index); sql != expected {
+		t.Errorf("SQL esperado %s, recebido %s", expected, sql)
+	}
+}
+
+func TestIndexMigration(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	migrator := sess.Migrator()
+	
+	if err := sess.AutoMigrate(ctx, &indexedAccount{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	
+	info, err := migrator.Introspect(ctx, "indexed_accounts")
+	if err != nil {
+		t.Fatalf("Falha na introspecção: %v", err)
+	}
+	for _, name := range []string{"idx_tenant_email", "uq_accounts_email", "idx_indexed_accounts_name"} {
+		if _, ok := info.Index(name); !ok {
+			t.Errorf("Índice %s não foi criado: %+v", name, info.Indexes)
+		}
+	}
+	
+	changes, err := migrator.Diff(ctx, &indexedAccount{})
+	if err != nil {
+		t.Fatalf("Falha ao calcular diferenças: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Modelo migrado não deveria ter diferenças: %+v", changes)
+	}
+}
+
+// Coluna NOT NULL com padrão adicionada a uma tabela já populada
+type defaultsTicket struct {
+	TableName struct{} `db:"defaults_tickets"`
+	ID        int      `db:"id,primarykey,autoincrement"`
+	Title     string   `db:"title,size:100"`
+}
+
+type defaultsTicketV2 struct {
+	TableName struct{} `db:"defaults_tickets"`
+	ID        int      `db:"id,primarykey,autoincrement"`
+	Title     string   `db:"title,size:100,comment:Título exibido na fila"`
+	Status    string   `db:"status,size:20,default:'aberto',check:status IN ('aberto', 'fechado')"`
+	Priority  int      `db:"priority,default:0"`
+}
+
+type defaultsTicketV3 struct {
+	TableName struct{} `db:"defaults_tickets"`
+	ID        int      `db:"id,primarykey,autoincrement"`
+	Title     string   `db:"title,size:100,comment:Título exibido na fila"`
+	Status    string   `db:"status,size:20,default:'aberto',check:status IN ('aberto', 'fechado', 'arquivado')"`
+	Priority  int      `db:"priority,default:0"`
+}
+
+func TestColumnDefaultsMapping(t *testing.T) {
+	mapping, err := schema.NewParser().Parse(&defaultsTicketV2{})
+	if err != nil {
+		t.Fatalf("Falha ao analisar modelo: %v", err)
+	}
+	
+	status := mapping.Fields[2]
+	if status.Default != "'aberto'" || status.Check != "status IN ('aberto', 'fechado')" {
+		t.Errorf("Opções de status inesperadas: %+v", status)
+	}
+	if comment := mapping.Fields[1].Comment; comment != "Título exibido na fila" {
+		t.Errorf("Comentário inesperado: %q", comment)
+	}
+	
+	expected := `ALTER TABLE "defaults_tickets" ADD COLUMN "status" VARCHAR(20) NOT NULL DEFAULT 'aberto' CONSTRAINT "chk_defaults_tickets_status" CHECK (status IN ('aberto', 'fechado'))`
+	if sql := dialect.NewPostgreSQL().AddColumnSQL("defaults_tickets", status); sql != expected {
+		t.Errorf("SQL esperado %s, recebido %s", expected, sql)
+	}
+	
+	expected = "ALTER TABLE `defaults_tickets` ADD COLUMN `title` VARCHAR(100) NOT NULL COMMENT 'Título exibido na fila'"
+	if sql := dialect.NewMySQL().AddColumnSQL("defaults_tickets", mapping.Fields[1]); sql != expected {
+		t.Errorf("SQL esperado %s, recebido %s", expected, sql)
+	}
+}
+
+func TestColumnDefaultsMigration(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	migrator := sess.Migrator()
+	
+	if err := sess.AutoMigrate(ctx, &defaultsTicket{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	if err := sess.Model(&defaultsTicket{}).Create(ctx, &defaultsTicket{Title: "primeiro"}); err != nil {
+		t.Fatalf("Falha ao criar ticket: %v", err)
+	}
+	
+	if err := sess.AutoMigrate(ctx, &defaultsTicketV2{}); err != nil {
+		t.Fatalf("Falha ao adicionar colunas com padrão: %v", err)
+	}
+	
+	var tickets []defaultsTicketV2
+	if err := sess.Model(&defaultsTicketV2{}).Find(ctx, &tickets); err != nil {
+		t.Fatalf("Falha ao buscar tickets: %v", err)
+	}
+	if len(tickets) != 1 || tickets[0].Status != "aberto" {
+		t.Errorf("Linha existente deveria receber o padrão, obtido %+v", tickets)
+	}
+	
+	if err := sess.Model(&defaultsTicketV2{}).Create(ctx, &defaultsTicketV2{Title: "x", Status: "invalido"}); err == nil {
+		t.Error("CHECK deveria rejeitar status inválido")
+	}
+	
+	changes, err := migrator.Diff(ctx, &defaultsTicketV2{})
+	if err != nil {
+		t.Fatalf("Falha ao calcular diferenças: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Modelo migrado não deveria ter diferenças: %+v", changes)
+	}
+	
+	// A expressão do CHECK mudou: a constraint é removida e recriada
+	changes, err = migrator.Diff(ctx, &defaultsTicketV3{})
+	if err != nil {
+		t.Fatalf("Falha ao calcular diferenças: %v", err)
+	}
+	if len(changes) != 2 || changes[0].Kind != schema.ChangeDropCheck || changes[1].Kind != schema.ChangeAddCheck {
+		t.Fatalf("Esperado recriar o CHECK, obtido %+v", changes)
+	}
+	
+	if err := sess.AutoMigrate(ctx, &defaultsTicketV3{}); err != nil {
+		t.Fatalf("Falha ao recriar CHECK: %v", err)
+	}
+	if err := sess.Model(&defaultsTicketV3{}).Create(ctx, &defaultsTicketV3{Title: "y", Status: "arquivado"}); err != nil {
+		t.Errorf("Novo CHECK deveria aceitar arquivado: %v", err)
+	}
+	
+	changes, err = migrator.Diff(ctx, &defaultsTicketV3{})
+	if err != nil {
+		t.Fatalf("Falha ao calcular diferenças: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("CHECK recriado não deveria ter diferenças: %+v", changes)
+	}
+}
+
+// Coluna opcional que passa a ser obrigatória
+type nullableNote struct {
+	TableName struct{} `db:"nullable_notes"`
+	ID        int      `db:"id,primarykey,autoincrement"`
+	Body      *string  `db:"body,size:100"`
+}
+
+type nullableNoteV2 struct {
+	TableName struct{} `db:"nullable_notes"`
+	ID        int      `db:"id,primarykey,autoincrement"`
+	Body      string   `db:"body,size:100"`
+}
+
+func TestNotNullChangeIsDestructive(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	
+	if err := sess.AutoMigrate(ctx, &nullableNote{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	if err := sess.Model(&nullableNote{}).Create(ctx, &nullableNote{}); err != nil {
+		t.Fatalf("Falha ao criar nota: %v", err)
+	}
+	
+	plan, err := sess.Migrator().PlanAutoMigrate(ctx, &nullableNoteV2{})
+	if err != nil {
+		t.Fatalf("Falha ao planejar migração: %v", err)
+	}
+	if len(plan.Changes) != 1 || plan.Changes[0].Kind != schema.ChangeAlterColumn || !plan.HasDestructive() {
+		t.Fatalf("NOT NULL deveria ser uma alteração destrutiva: %+v", plan.Changes)
+	}
+	
+	// Sem AllowDestructive a coluna continua aceitando NULL
+	if err := sess.AutoMigrate(ctx, &nullableNoteV2{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	changes, err := sess.Migrator().Diff(ctx, &nullableNoteV2{})
+	if err != nil {
+		t.Fatalf("Falha ao calcular diferenças: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Errorf("Alteração destrutiva não deveria ser aplicada: %+v", changes)
+	}
+}
@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+	
+	"github.com/Flavio-coutinho/kiara-orm/query"
+	"github.com/Flavio-coutinho/kiara-orm/schema"
+	"github.com/Flavio-coutinho/kiara-orm/tests/models"
+	"github.com/Flavio-coutinho/kiara-orm/types"
+)
+
+type auditEvent struct {
+	ID        int   `db:"id,primarykey,autoincrement"`
+	CreatedAt int64 `db:"created,autoCreateTime:milli"`
+	UpdatedAt int64 `db:"updated,autoUpdateTime"`
+}
+
+func TestTimestampParsing(t *testing.T) {
+	mapping, err := schema.NewParser().Parse(&auditEvent{})
+	if err != nil {
+		t.Fatalf("Falha ao analisar modelo: %v", err)
+	}
+	
+	if mapping.Fields[1].AutoCreateTime != types.AutoTimeUnixMilli {
+		t.Errorf("Esperado autoCreateTime em milissegundos, recebido %v", mapping.Fields[1].AutoCreateTime)
+	}
+	
+	if mapping.Fields[2].AutoUpdateTime != types.AutoTimeUnix {
+		t.Errorf("Esperado autoUpdateTime em segundos, recebido %v", mapping.Fields[2].AutoUpdateTime)
+	}
+	
+	userMapping, _ := schema.NewParser().Parse(&models.User{})
+	for _, field := range userMapping.Fields {
+		if field.Name == "created_at" && field.AutoCreateTime != types.AutoTimeDateTime {
+			t.Error("created_at deveria ser reconhecido por convenção")
+		}
+	}
+}
+
+func TestTimestamps(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	
+	if err := sess.AutoMigrate(ctx, &models.User{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	sess.SetClock(func() time.Time { return now })
+	
+	user := &models.User{
+		Name:  "Clock User",
+		Email: "clock@example.com",
+		Age:   30,
+	}
+	
+	if err := sess.Model(&models.User{}).Create(ctx, user); err != nil {
+		t.Fatalf("Falha ao criar usuário: %v", err)
+	}
+	
+	if !user.CreatedAt.Equal(now) || !user.UpdatedAt.Equal(now) {
+		t.Errorf("Timestamps esperados %v, recebidos %v/%v", now, user.CreatedAt, user.UpdatedAt)
+	}
+	
+	later := now.Add(time.Hour)
+	sess.SetClock(func() time.Time { return later })
+	
+	err := sess.Model(&models.User{}).Update(ctx, user,
+		query.Condition{Column: "email", Operation: query.OpEq, Value: "clock@example.com"})
+	if err != nil {
+		t.Fatalf("Falha ao atualizar usuário: %v", err)
+	}
+	
+	if !user.CreatedAt.Equal(now) {
+		t.Error("created_at não deveria mudar na atualização")
+	}
+	
+	if !user.UpdatedAt.Equal(later) {
+		t.Errorf("updated_at esperado %v, recebido %v", later, user.UpdatedAt)
+	}
+}
+
+func TestBulkUpdateTimestamps(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	
+	if err := sess.AutoMigrate(ctx, &models.User{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	sess.SetClock(func() time.Time { return now })
+	
+	user := &models.User{
+		Name:  "Bulk Clock User",
+		Email: "bulk-clock@example.com",
+		Age:   30,
+	}
+	if err := sess.Model(&models.User{}).Create(ctx, user); err != nil {
+		t.Fatalf("Falha ao criar usuário: %v", err)
+	}
+	
+	later := now.Add(time.Hour)
+	sess.SetClock(func() time.Time { return later })
+	
+	user.Name = "Bulk Clock User 2"
+	if err := sess.Model(&models.User{}).BulkUpdate(ctx, []interface{}{user}, nil); err != nil {
+		t.Fatalf("Falha na atualização em lote: %v", err)
+	}
+	
+	if !user.CreatedAt.Equal(now) {
+		t.Error("created_at não deveria mudar na atualização em lote")
+	}
+	if !user.UpdatedAt.Equal(later) {
+		t.Errorf("updated_at esperado %v, recebido %v", later, user.UpdatedAt)
+	}
+}
+
+func TestSoftDeleteTimestamps(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	
+	if err := sess.AutoMigrate(ctx, &models.User{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	sess.SetClock(func() time.Time { return now })
+	
+	user := &models.User{
+		Name:  "Soft Clock User",
+		Email: "soft-clock@example.com",
+		Age:   30,
+	}
+	if err := sess.Model(&models.User{}).Create(ctx, user); err != nil {
+		t.Fatalf("Falha ao criar usuário: %v", err)
+	}
+	
+	later := now.Add(time.Hour)
+	sess.SetClock(func() time.Time { return later })
+	
+	// SoftDelete atualiza um mapa de colunas, sem campos de struct
+	byID := query.Condition{Column: "id", Operation: query.OpEq, Value: user.ID}
+	if err := sess.Model(&models.User{}).SoftDelete(ctx, byID); err != nil {
+		t.Fatalf("Falha na exclusão lógica: %v", err)
+	}
+	
+	var users []models.User
+	if err := sess.Model(&models.User{}).WithTrashed().Find(ctx, &users, byID); err != nil {
+		t.Fatalf("Falha ao buscar usuário: %v", err)
+	}
+	if len(users) != 1 || users[0].DeletedAt == nil || !users[0].UpdatedAt.Equal(later) {
+		t.Errorf("Esperado usuário excluído em %v, recebido %+v", later, users)
+	}
+	
+	if err := sess.Model(&models.User{}).Restore(ctx, byID); err != nil {
+		t.Fatalf("Falha ao restaurar usuário: %v", err)
+	}
+}
@@ -0,0 +1,299 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+	
+	"github.com/Flavio-coutinho/kiara-orm/dialect"
+	"github.com/Flavio-coutinho/kiara-orm/hooks"
+	"github.com/Flavio-coutinho/kiara-orm/query"
+	"github.com/Flavio-coutinho/kiara-orm/session"
+	"github.com/Flavio-coutinho/kiara-orm/tests/models"
+	"github.com/Flavio-coutinho/kiara-orm/transaction"
+)
+
+func TestNestedTransaction(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	
+	if err := sess.AutoMigrate(ctx, &models.Document{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	
+	errInner := errors.New("falha no escopo interno")
+	
+	err := sess.Transaction(ctx, func(tx *session.Session) error {
+		if err := tx.Model(&models.Document{}).Create(ctx, &models.Document{Title: "Externo"}); err != nil {
+			return err
+		}
+		
+		err := tx.Transaction(ctx, func(inner *session.Session) error {
+			if inner.TxDepth() != 2 {
+				t.Errorf("Profundidade esperada 2, recebida %d", inner.TxDepth())
+			}
+			
+			if err := inner.Model(&models.Document{}).Create(ctx, &models.Document{Title: "Interno"}); err != nil {
+				return err
+			}
+			return errInner
+		})
+		
+		if !errors.Is(err, errInner) {
+			t.Errorf("Esperado erro do escopo interno, recebido %v", err)
+		}
+		return nil
+	})
+	
+	if err != nil {
+		t.Fatalf("Falha na transação externa: %v", err)
+	}
+	
+	var docs []models.Document
+	if err := sess.Model(&models.Document{}).Find(ctx, &docs,
+		query.Condition{Column: "title", Operation: query.OpIn, Value: []string{"Externo", "Interno"}}); err != nil {
+		t.Fatalf("Falha ao buscar documentos: %v", err)
+	}
+	
+	if len(docs) != 1 || docs[0].Title != "Externo" {
+		t.Errorf("Esperado apenas o documento externo, recebido %+v", docs)
+	}
+}
+
+func TestTransactionOptions(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	
+	defaults := transaction.Options{Isolation: sql.LevelReadCommitted}
+	sess.SetTxOptions(defaults)
+	
+	err := sess.Transaction(ctx, func(tx *session.Session) error {
+		if tx.TxOptions() != defaults {
+			t.Errorf("Opções padrão esperadas %s, recebidas %s", defaults, tx.TxOptions())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Falha na transação: %v", err)
+	}
+	
+	opts := transaction.Options{Isolation: sql.LevelSerializable, ReadOnly: true, Timeout: 50 * time.Millisecond}
+	err = sess.TransactionWith(ctx, opts, func(tx *session.Session) error {
+		if tx.TxOptions() != opts {
+			t.Errorf("Opções esperadas %s, recebidas %s", opts, tx.TxOptions())
+		}
+		
+		time.Sleep(100 * time.Millisecond)
+		var docs []models.Document
+		return tx.Model(&models.Document{}).Find(ctx, &docs)
+	})
+	
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Esperado prazo expirado, recebido %v", err)
+	}
+}
+
+func TestContextTransaction(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	
+	if err := sess.AutoMigrate(ctx, &models.Document{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	
+	// Repositório que conhece apenas o contexto
+	create := func(ctx context.Context, title string) error {
+		return sess.Model(&models.Document{}).Create(ctx, &models.Document{Title: title})
+	}
+	
+	errAbort := errors.New("abortar")
+	err := sess.TransactionContext(ctx, func(ctx context.Context) error {
+		if _, ok := session.TxFromContext(ctx); !ok {
+			t.Fatal("Contexto deveria carregar a transação")
+		}
+		if err := create(ctx, "Ambiente"); err != nil {
+			return err
+		}
+		
+		// Fora da transação o registro ainda não é visível
+		var outside []models.Document
+		if err := sess.Model(&models.Document{}).Find(session.WithoutTx(ctx), &outside,
+			query.Condition{Column: "title", Operation: query.OpEq, Value: "Ambiente"}); err != nil {
+			return err
+		}
+		if len(outside) != 0 {
+			t.Errorf("Registro não deveria ser visível fora da transação")
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	
+	var docs []models.Document
+	if err := sess.Model(&models.Document{}).Find(ctx, &docs,
+		query.Condition{Column: "title", Operation: query.OpEq, Value: "Ambiente"}); err != nil {
+		t.Fatalf("Falha ao buscar: %v", err)
+	}
+	if len(docs) != 0 {
+		t.Errorf("Criação pelo repositório deveria ter sido desfeita")
+	}
+}
+
+func TestTransactionCallbacks(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	
+	if err := sess.AutoMigrate(ctx, &models.Document{}); err != nil {
+		t.Fatalf("Falha na migração: %v", err)
+	}
+	
+	var events []string
+	record := func(name string) transaction.Callback {
+		return func(ctx context.Context) error {
+			events = append(events, name)
+			return errors.New("erros de callback são apenas registrados")
+		}
+	}
+	
+	sess.RegisterHook(hooks.AfterCreateCommit, func(ctx context.Context, value interface{}) error {
+		events = append(events, "created:"+value.(*models.Document).Title)
+		return nil
+	})
+	
+	t.Run("Commit", func(t *testing.T) {
+		events = nil
+		err := sess.Transaction(ctx, func(tx *session.Session) error {
+			tx.OnCommit(record("primeiro"))
+			tx.OnRollback(record("rollback"))
+			if err := tx.Model(&models.Document{}).Create(ctx, &models.Document{Title: "Evento"}); err != nil {
+				return err
+			}
+			tx.OnCommit(record("segundo"))
+			
+			if len(events) != 0 {
+				t.Errorf("Callbacks não deveriam executar antes do commit: %v", events)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Transação falhou: %v", err)
+		}
+		
+		expected := []string{"primeiro", "created:Evento", "segundo"}
+		if fmt.Sprint(events) != fmt.Sprint(expected) {
+			t.Errorf("Esperado %v, obtido %v", expected, events)
+		}
+	})
+	
+	t.Run("Rollback", func(t *testing.T) {
+		events = nil
+		_ = sess.Transaction(ctx, func(tx *session.Session) error {
+			tx.OnCommit(record("commit"))
+			tx.OnRollback(record("rollback"))
+			return errors.New("falha")
+		})
+		
+		if fmt.Sprint(events) != fmt.Sprint([]string{"rollback"}) {
+			t.Errorf("Apenas o callback de rollback deveria executar: %v", events)
+		}
+	})
+}
+
+// pgError imita os erros do pgx/lib/pq, que expõem o SQLSTATE
+type pgError struct{ code string }
+
+func (e *pgError) Error() string    { return "pg: " + e.code }
+func (e *pgError) SQLState() string { return e.code }
+
+// mysqlError imita o *mysql.MySQLError
+type mysqlError struct{ Number uint16 }
+
+func (e *mysqlError) Error() string { return fmt.Sprintf("Error %d", e.Number) }
+
+// opaqueDriverError expõe o código só pelos campos, sem repeti-lo na mensagem:
+// a classificação depende de o erro continuar alcançável pela cadeia
+type opaqueDriverError struct {
+	state  string
+	Number uint16
+}
+
+func (e *opaqueDriverError) Error() string    { return "falha no driver" }
+func (e *opaqueDriverError) SQLState() string { return e.state }
+
+// failingConnector abre conexões cujas consultas falham com err, como um driver real
+type failingConnector struct{ err error }
+
+func (c failingConnector) Connect(context.Context) (driver.Conn, error) { return failingConn(c), nil }
+func (c failingConnector) Driver() driver.Driver                       { return nil }
+
+type failingConn struct{ err error }
+
+func (c failingConn) Prepare(string) (driver.Stmt, error) { return nil, c.err }
+func (c failingConn) Close() error                        { return nil }
+func (c failingConn) Begin() (driver.Tx, error)           { return nil, c.err }
+
+// executorError retorna o erro do Executor para uma consulta que falha com err
+func executorError(err error) error {
+	db := sql.OpenDB(failingConnector{err})
+	defer db.Close()
+	
+	builder := query.NewBuilder(dialect.NewPostgreSQL()).Table("documents")
+	_, err = query.NewExecutor(db, builder).Rows(context.Background())
+	return err
+}
+
+func TestRetryableErrors(t *testing.T) {
+	cases := []struct {
+		name      string
+		dialect   dialect.Dialect
+		err       error
+		retryable bool
+	}{
+		{"postgres serialization", dialect.NewPostgreSQL(), &pgError{"40001"}, true},
+		{"postgres deadlock", dialect.NewPostgreSQL(), fmt.Errorf("erro ao fazer commit: %w", &pgError{"40P01"}), true},
+		{"postgres unique", dialect.NewPostgreSQL(), &pgError{"23505"}, false},
+		{"postgres code in message", dialect.NewPostgreSQL(), errors.New("pedido 40001 não encontrado"), false},
+		{"postgres executor", dialect.NewPostgreSQL(), executorError(&opaqueDriverError{state: "40001"}), true},
+		{"mysql executor", dialect.NewMySQL(), executorError(&opaqueDriverError{Number: 1213}), true},
+		{"mysql deadlock", dialect.NewMySQL(), &mysqlError{1213}, true},
+		{"mysql lock timeout", dialect.NewMySQL(), &mysqlError{1205}, true},
+		{"mysql duplicate", dialect.NewMySQL(), &mysqlError{1062}, false},
+		{"sqlite busy", dialect.NewSQLite(), errors.New("database is locked"), true},
+		{"sqlite constraint", dialect.NewSQLite(), errors.New("UNIQUE constraint failed"), false},
+	}
+	
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.dialect.IsRetryableError(tc.err); got != tc.retryable {
+				t.Errorf("IsRetryableError(%v) = %v, esperado %v", tc.err, got, tc.retryable)
+			}
+		})
+	}
+}
+
+func TestTransactionRetry(t *testing.T) {
+	sess := setupTestSession(t)
+	ctx := context.Background()
+	
+	sess.SetRetryPolicy(transaction.RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond})
+	
+	attempts := 0
+	err := sess.Transaction(ctx, func(tx *session.Session) error {
+		attempts++
+		if attempts < 3 {
+			return &mysqlError{1213}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transação deveria ter sucesso após novas tentativas: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Esperado 3 tentativas, obtido %d", attempts)
+	}
+}
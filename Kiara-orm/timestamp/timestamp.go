@@ -0,0 +1,89 @@
+package timestamp
+
+import (
+	"reflect"
+	"time"
+	
+	"github.com/Flavio-coutinho/Kiara-orm/types"
+)
+
+// Clock retorna o horário atual. Pode ser substituído por um relógio fixo em testes
+type Clock func() time.Time
+
+// SetCreateTime preenche os campos de timestamp automático de um novo registro.
+// Campos já preenchidos pelo usuário são mantidos.
+func SetCreateTime(mapping *types.TableMapping, record reflect.Value, now time.Time) {
+	record = reflect.Indirect(record)
+	if record.Kind() != reflect.Struct {
+		return
+	}
+	
+	for _, field := range mapping.Fields {
+		mode := field.AutoCreateTime
+		if mode == types.AutoTimeNone {
+			mode = field.AutoUpdateTime
+		}
+		if mode == types.AutoTimeNone {
+			continue
+		}
+		
+		value := record.FieldByName(field.FieldName)
+		if value.IsValid() && value.IsZero() {
+			setTime(value, mode, now)
+		}
+	}
+}
+
+// SetUpdateTime preenche os campos autoUpdateTime de um registro alterado.
+// Valores que não são structs (ex.: mapas de colunas) são ignorados.
+func SetUpdateTime(mapping *types.TableMapping, record reflect.Value, now time.Time) {
+	record = reflect.Indirect(record)
+	if record.Kind() != reflect.Struct {
+		return
+	}
+	
+	for _, field := range mapping.Fields {
+		if field.AutoUpdateTime == types.AutoTimeNone {
+			continue
+		}
+		
+		if value := record.FieldByName(field.FieldName); value.IsValid() {
+			setTime(value, field.AutoUpdateTime, now)
+		}
+	}
+}
+
+// Value converte o horário para o valor armazenado na coluna conforme o modo
+func Value(mode types.AutoTime, now time.Time) interface{} {
+	switch mode {
+	case types.AutoTimeUnix:
+		return now.Unix()
+	case types.AutoTimeUnixMilli:
+		return now.UnixMilli()
+	default:
+		return now
+	}
+}
+
+// setTime atribui o horário ao campo, tratando ponteiros e inteiros
+func setTime(value reflect.Value, mode types.AutoTime, now time.Time) {
+	if !value.CanSet() {
+		return
+	}
+	
+	if value.Kind() == reflect.Ptr {
+		ptr := reflect.New(value.Type().Elem())
+		setTime(ptr.Elem(), mode, now)
+		value.Set(ptr)
+		return
+	}
+	
+	switch value.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		value.SetInt(Value(mode, now).(int64))
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		value.SetUint(uint64(Value(mode, now).(int64)))
+	default:
+		value.Set(reflect.ValueOf(now))
+	}
+}
@@ -0,0 +1,91 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Callback é executada quando o resultado da transação é conhecido
+type Callback func(ctx context.Context) error
+
+// txCallback guarda a callback e o nível de aninhamento em que foi registrada
+type txCallback struct {
+	fn     Callback
+	commit bool
+	depth  int
+}
+
+// OnCommit registra fn para ser executada após o commit da transação.
+// Se o savepoint em que foi registrada for desfeito, fn é descartada.
+func (tm *TxManager) OnCommit(tx *sql.Tx, fn Callback) {
+	tm.register(tx, fn, true)
+}
+
+// OnRollback registra fn para ser executada após o rollback da transação
+// ou do savepoint em que foi registrada
+func (tm *TxManager) OnRollback(tx *sql.Tx, fn Callback) {
+	tm.register(tx, fn, false)
+}
+
+// register adiciona a callback no nível atual da transação
+func (tm *TxManager) register(tx *sql.Tx, fn Callback, commit bool) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.callbacks[tx] = append(tm.callbacks[tx], txCallback{fn: fn, commit: commit, depth: tm.depths[tx]})
+}
+
+// finish executa, em ordem de registro, as callbacks do resultado da transação
+func (tm *TxManager) finish(ctx context.Context, tx *sql.Tx, committed bool) {
+	tm.mu.Lock()
+	callbacks := tm.callbacks[tx]
+	delete(tm.callbacks, tx)
+	tm.mu.Unlock()
+	
+	tm.runCallbacks(ctx, callbacks, committed)
+}
+
+// releaseCallbacks transfere ao nível externo as callbacks de um savepoint confirmado
+func (tm *TxManager) releaseCallbacks(tx *sql.Tx, depth int) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	
+	for i := range tm.callbacks[tx] {
+		if tm.callbacks[tx][i].depth >= depth {
+			tm.callbacks[tx][i].depth = depth - 1
+		}
+	}
+}
+
+// rollbackCallbacks remove as callbacks de um savepoint desfeito e executa as de rollback
+func (tm *TxManager) rollbackCallbacks(ctx context.Context, tx *sql.Tx, depth int) {
+	tm.mu.Lock()
+	var kept, discarded []txCallback
+	for _, cb := range tm.callbacks[tx] {
+		if cb.depth >= depth {
+			discarded = append(discarded, cb)
+		} else {
+			kept = append(kept, cb)
+		}
+	}
+	tm.callbacks[tx] = kept
+	tm.mu.Unlock()
+	
+	tm.runCallbacks(ctx, discarded, false)
+}
+
+// runCallbacks executa as callbacks do resultado; erros são apenas registrados no log
+func (tm *TxManager) runCallbacks(ctx context.Context, callbacks []txCallback, committed bool) {
+	for _, cb := range callbacks {
+		if cb.commit != committed {
+			continue
+		}
+		
+		if err := cb.fn(ctx); err != nil && tm.logger != nil {
+			if committed {
+				tm.logger.Error(ctx, "Erro em callback após commit: %v", err)
+			} else {
+				tm.logger.Error(ctx, "Erro em callback após rollback: %v", err)
+			}
+		}
+	}
+}
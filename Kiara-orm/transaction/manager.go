@@ -3,39 +3,123 @@ package transaction
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
+	"sync"
+	
+	"github.com/Flavio-coutinho/Kiara-orm/dialect"
+	"github.com/Flavio-coutinho/Kiara-orm/logger"
+	"github.com/Flavio-coutinho/Kiara-orm/metrics"
 )
 
-// TxManager gerencia transações do banco de dados
+// TxManager gerencia transações do banco de dados e o nível de
+// aninhamento (savepoints) de cada transação em andamento
 type TxManager struct {
-	db *sql.DB
+	db      *sql.DB
+	dialect dialect.Dialect
+	mu      sync.Mutex
+	depths  map[*sql.Tx]int
+	retry   RetryPolicy
+	metrics *metrics.Collector
+	logger  logger.Logger
+	
+	callbacks map[*sql.Tx][]txCallback
 }
 
 // NewTxManager cria uma nova instância do TxManager
-func NewTxManager(db *sql.DB) *TxManager {
-	return &TxManager{db: db}
+func NewTxManager(db *sql.DB, dialect dialect.Dialect) *TxManager {
+	return &TxManager{
+		db:      db,
+		dialect: dialect,
+		depths:  make(map[*sql.Tx]int),
+		retry:   DefaultRetryPolicy(),
+		
+		callbacks: make(map[*sql.Tx][]txCallback),
+	}
+}
+
+// SetRetryPolicy define a política de repetição de transações
+func (tm *TxManager) SetRetryPolicy(policy RetryPolicy) {
+	tm.retry = policy
+}
+
+// SetMetrics define o coletor que recebe as métricas de novas tentativas
+func (tm *TxManager) SetMetrics(collector *metrics.Collector) {
+	tm.metrics = collector
+}
+
+// SetLogger define o logger que registra os erros das callbacks
+func (tm *TxManager) SetLogger(logger logger.Logger) {
+	tm.logger = logger
 }
 
 // RunInTransaction executa uma função dentro de uma transação
 func (tm *TxManager) RunInTransaction(ctx context.Context, fn func(tx *sql.Tx) error) error {
-	tx, err := tm.db.BeginTx(ctx, nil)
+	return tm.RunWithOptions(ctx, Options{}, fn)
+}
+
+// RunWithOptions executa uma função dentro de uma transação com as opções informadas.
+// Ao fim do Timeout o contexto da transação expira e ela é desfeita. Falhas
+// transitórias reconhecidas pelo dialeto repetem a função inteira conforme a RetryPolicy.
+func (tm *TxManager) RunWithOptions(ctx context.Context, opts Options, fn func(tx *sql.Tx) error) error {
+	for attempt := 0; ; attempt++ {
+		err := tm.runOnce(ctx, opts, fn)
+		if err == nil || attempt >= tm.retry.MaxRetries || !tm.dialect.IsRetryableError(err) {
+			return err
+		}
+		
+		if tm.metrics != nil {
+			tm.metrics.AddMetric(metrics.TransactionRetry, 1, map[string]string{
+				"attempt": strconv.Itoa(attempt + 1),
+			})
+		}
+		
+		if waitErr := tm.retry.wait(ctx, attempt); waitErr != nil {
+			return fmt.Errorf("transação cancelada: %w (erro original: %v)", waitErr, err)
+		}
+	}
+}
+
+// runOnce executa uma única tentativa da transação
+func (tm *TxManager) runOnce(ctx context.Context, opts Options, fn func(tx *sql.Tx) error) error {
+	// As callbacks recebem o contexto original, que não expira com o Timeout
+	parent := ctx
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	
+	tx, err := tm.db.BeginTx(ctx, opts.TxOptions())
 	if err != nil {
-		return fmt.Errorf("erro ao iniciar transação: %v", err)
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
 	}
 	
+	tm.enter(tx)
+	defer tm.forget(tx)
+	
 	// Garante que a transação será finalizada
 	defer func() {
 		if p := recover(); p != nil {
 			// Em caso de panic, faz rollback e re-panic
 			_ = tx.Rollback()
+			tm.finish(parent, tx, false)
 			panic(p)
 		}
 	}()
 	
 	// Executa a função dentro da transação
 	if err := fn(tx); err != nil {
+		defer tm.finish(parent, tx, false)
+		
+		// Prazo expirado ou contexto cancelado: o database/sql já desfez a transação
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("transação cancelada: %w (erro original: %v)", ctxErr, err)
+		}
+		
 		// Em caso de erro, faz rollback
-		if rbErr := tx.Rollback(); rbErr != nil {
+		if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
 			return fmt.Errorf("erro ao fazer rollback: %v (erro original: %v)", rbErr, err)
 		}
 		return err
@@ -43,8 +127,78 @@ func (tm *TxManager) RunInTransaction(ctx context.Context, fn func(tx *sql.Tx) e
 	
 	// Commit da transação
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("erro ao fazer commit: %v", err)
+		tm.finish(parent, tx, false)
+		return fmt.Errorf("erro ao fazer commit: %w", err)
 	}
 	
+	tm.finish(parent, tx, true)
 	return nil
-} 
\ No newline at end of file
+}
+
+// RunNested executa uma função em um savepoint da transação já aberta.
+// Em caso de erro apenas as alterações do escopo interno são desfeitas.
+func (tm *TxManager) RunNested(ctx context.Context, tx *sql.Tx, fn func(tx *sql.Tx) error) error {
+	depth := tm.enter(tx)
+	defer tm.leave(tx)
+	
+	name := fmt.Sprintf("sp_%d", depth)
+	if _, err := tx.ExecContext(ctx, tm.dialect.SavepointSQL(name)); err != nil {
+		return fmt.Errorf("erro ao criar savepoint: %w", err)
+	}
+	
+	defer func() {
+		if p := recover(); p != nil {
+			_, _ = tx.ExecContext(ctx, tm.dialect.RollbackToSavepointSQL(name))
+			tm.rollbackCallbacks(ctx, tx, depth)
+			panic(p)
+		}
+	}()
+	
+	if err := fn(tx); err != nil {
+		defer tm.rollbackCallbacks(ctx, tx, depth)
+		
+		if _, rbErr := tx.ExecContext(ctx, tm.dialect.RollbackToSavepointSQL(name)); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			return fmt.Errorf("erro ao fazer rollback do savepoint: %v (erro original: %v)", rbErr, err)
+		}
+		// O savepoint continua existindo após o rollback e é liberado em seguida
+		_, _ = tx.ExecContext(ctx, tm.dialect.ReleaseSavepointSQL(name))
+		return err
+	}
+	
+	if _, err := tx.ExecContext(ctx, tm.dialect.ReleaseSavepointSQL(name)); err != nil {
+		return fmt.Errorf("erro ao liberar savepoint: %w", err)
+	}
+	
+	tm.releaseCallbacks(tx, depth)
+	return nil
+}
+
+// Depth retorna o nível de aninhamento da transação: 1 para a transação
+// principal, 2 ou mais dentro de savepoints e 0 se ela não está ativa
+func (tm *TxManager) Depth(tx *sql.Tx) int {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	return tm.depths[tx]
+}
+
+// enter incrementa o nível de aninhamento e o retorna
+func (tm *TxManager) enter(tx *sql.Tx) int {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.depths[tx]++
+	return tm.depths[tx]
+}
+
+// leave decrementa o nível de aninhamento
+func (tm *TxManager) leave(tx *sql.Tx) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.depths[tx]--
+}
+
+// forget remove a transação finalizada
+func (tm *TxManager) forget(tx *sql.Tx) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	delete(tm.depths, tx)
+}
@@ -0,0 +1,30 @@
+package transaction
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Options configura uma transação
+type Options struct {
+	Isolation sql.IsolationLevel // Nível de isolamento (padrão do banco se LevelDefault)
+	ReadOnly  bool               // Transação somente leitura
+	Timeout   time.Duration      // Prazo máximo da transação (0 = sem limite)
+}
+
+// TxOptions converte as opções para o formato de database/sql
+func (o Options) TxOptions() *sql.TxOptions {
+	if o.Isolation == sql.LevelDefault && !o.ReadOnly {
+		return nil
+	}
+	return &sql.TxOptions{
+		Isolation: o.Isolation,
+		ReadOnly:  o.ReadOnly,
+	}
+}
+
+// String descreve as opções, para uso em logs
+func (o Options) String() string {
+	return fmt.Sprintf("isolation=%s read_only=%t timeout=%s", o.Isolation, o.ReadOnly, o.Timeout)
+}
@@ -0,0 +1,53 @@
+package transaction
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy configura a repetição de transações que falham por conflitos
+// transitórios, como falhas de serialização e deadlocks
+type RetryPolicy struct {
+	MaxRetries int           // Número máximo de novas tentativas (0 desabilita)
+	BaseDelay  time.Duration // Espera antes da primeira nova tentativa
+	MaxDelay   time.Duration // Limite da espera entre tentativas
+}
+
+// DefaultRetryPolicy retorna a política padrão: até 3 novas tentativas com
+// espera exponencial a partir de 10ms, limitada a 1s
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  10 * time.Millisecond,
+		MaxDelay:   time.Second,
+	}
+}
+
+// Backoff calcula a espera da tentativa (a partir de 0) com jitter: um valor
+// aleatório entre metade e o total do atraso exponencial
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << attempt
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// wait aguarda a espera da tentativa ou o cancelamento do contexto
+func (p RetryPolicy) wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(p.Backoff(attempt))
+	defer timer.Stop()
+	
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
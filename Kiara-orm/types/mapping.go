@@ -14,21 +14,95 @@ const (
     Time
 )
 
+// AutoTime representa o modo de preenchimento automático de timestamps
+type AutoTime int
+
+const (
+    AutoTimeNone AutoTime = iota
+    AutoTimeDateTime
+    AutoTimeUnix
+    AutoTimeUnixMilli
+)
+
 // FieldMapping representa o mapeamento de um campo da struct para o banco de dados
 type FieldMapping struct {
     Name         string
+    FieldName    string // Nome do campo na struct Go
     Type         DataType
     Size         int
     IsPrimaryKey bool
     IsAutoInc    bool
     IsNullable   bool
     IsUnique     bool
+    IsVersion    bool // Coluna de versão para locking otimista
+    
+    Default string // Expressão SQL do valor padrão, como 0, 'ativo' ou CURRENT_TIMESTAMP
+    Check   string // Condição da constraint CHECK da coluna
+    Comment string
+    
+    // Preenchimento automático de created_at/updated_at
+    AutoCreateTime AutoTime
+    AutoUpdateTime AutoTime
+}
+
+// RelationKind representa o tipo de relacionamento declarado pela tag rel
+type RelationKind string
+
+const (
+    RelBelongsTo  RelationKind = "belongs_to"
+    RelHasOne     RelationKind = "has_one"
+    RelHasMany    RelationKind = "has_many"
+    RelManyToMany RelationKind = "many2many"
+)
+
+// RelationMapping representa um relacionamento declarado por tag na struct
+type RelationMapping struct {
+    FieldName        string
+    Kind             RelationKind
+    Model            interface{} // Instância do modelo relacionado
+    ForeignKey       string
+    ReferenceKey     string
+    JoinTable        string
+    JoinForeignKey   string
+    JoinReferenceKey string
+    AssociationKey   string
+    PolymorphicType  string // Campo do relacionado que guarda o tipo do dono
+    PolymorphicValue string // Valor gravado no campo de tipo
+    Preload          bool
+    
+    // Constraint cria a chave estrangeira de um belongs_to no banco
+    Constraint bool
+    OnDelete   string
+    OnUpdate   string
+}
+
+// ForeignKeyMapping representa uma chave estrangeira da tabela
+type ForeignKeyMapping struct {
+    Name            string // Nome da constraint (fk_<tabela>_<coluna>)
+    Column          string
+    ReferenceTable  string
+    ReferenceColumn string
+    OnDelete        string // CASCADE, SET NULL, RESTRICT, NO ACTION ou vazio
+    OnUpdate        string
+}
+
+// IndexMapping representa um índice declarado pelas tags index e uniqueIndex
+type IndexMapping struct {
+    Name    string
+    Columns []string // Em ordem de prioridade
+    Unique  bool
+    Where   string // Condição do índice parcial (PostgreSQL e SQLite)
+    Method  string // Método de acesso, como BTREE, HASH ou GIN
 }
 
 // TableMapping representa o mapeamento de uma struct para uma tabela
 type TableMapping struct {
     TableName string
     Fields    []FieldMapping
+    Relations []RelationMapping
+    
+    ForeignKeys []ForeignKeyMapping
+    Indexes     []IndexMapping
 }
 
 // TypeMapper é responsável por converter tipos Go para tipos do banco de dados
@@ -0,0 +1,73 @@
+package versioning
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	
+	"github.com/Flavio-coutinho/Kiara-orm/types"
+)
+
+// ErrStaleObject indica que o registro foi alterado por outra transação
+var ErrStaleObject = errors.New("registro desatualizado")
+
+// StaleObjectError detalha um conflito de versão em uma atualização
+type StaleObjectError struct {
+	Table   string
+	Version int64
+}
+
+// Error implementa a interface error
+func (e *StaleObjectError) Error() string {
+	return fmt.Sprintf("%v: %s não está mais na versão %d", ErrStaleObject, e.Table, e.Version)
+}
+
+// Unwrap permite usar errors.Is(err, ErrStaleObject)
+func (e *StaleObjectError) Unwrap() error {
+	return ErrStaleObject
+}
+
+// Field retorna o campo de versão do mapeamento, se existir
+func Field(mapping *types.TableMapping) (types.FieldMapping, bool) {
+	for _, field := range mapping.Fields {
+		if field.IsVersion {
+			return field, true
+		}
+	}
+	return types.FieldMapping{}, false
+}
+
+// Get retorna a versão atual do registro
+func Get(record reflect.Value, field types.FieldMapping) int64 {
+	value := reflect.Indirect(record).FieldByName(field.FieldName)
+	
+	switch value.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return value.Int()
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return int64(value.Uint())
+	}
+	return 0
+}
+
+// Set define a versão do registro
+func Set(record reflect.Value, field types.FieldMapping, version int64) {
+	value := reflect.Indirect(record).FieldByName(field.FieldName)
+	if !value.CanSet() {
+		return
+	}
+	
+	switch value.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		value.SetInt(version)
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		value.SetUint(uint64(version))
+	}
+}
+
+// Init define a versão inicial de um registro novo
+func Init(mapping *types.TableMapping, record reflect.Value) {
+	if field, ok := Field(mapping); ok && Get(record, field) == 0 {
+		Set(record, field, 1)
+	}
+}